@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/wuwenrufeng/issue2md/internal/config"
 	"github.com/wuwenrufeng/issue2md/internal/converter"
@@ -16,7 +21,16 @@ import (
 // stdout: 标准输出 writer
 // stderr: 标准错误 writer
 // 返回: exitCode (0: 成功, 1: 错误)
+// 收到 SIGINT/SIGTERM 时取消进行中的请求
 func Run(argv []string, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return RunContext(ctx, argv, stdout, stderr)
+}
+
+// RunContext 与 Run 相同，但由调用方通过 ctx 控制取消
+func RunContext(ctx context.Context, argv []string, stdout, stderr io.Writer) int {
 	// 1. 加载配置
 	cfg, exitCode := config.LoadFromFlags(argv, stdout, stderr)
 	if exitCode != -1 {
@@ -24,59 +38,89 @@ func Run(argv []string, stdout, stderr io.Writer) int {
 	}
 	// 此时 cfg != nil，程序继续执行
 
-	// 2. 解析URL
-	resource, err := parser.ParseURL(cfg.URL)
+	// 2. 准备缓存（需要时先清空）
+	cache, err := newCache(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "缓存错误: %v\n", err)
+		return 1
+	}
+	if cfg.ClearCache {
+		if err := cache.Clear(); err != nil {
+			fmt.Fprintf(stderr, "缓存错误: %v\n", err)
+			return 1
+		}
+		if cfg.URL == "" {
+			fmt.Fprintf(stdout, "已清空缓存: %s\n", cache.Dir())
+			return 0
+		}
+	}
+
+	// 3. 解析URL
+	resource, err := parser.ParseURL(cfg.URL, cfg.Hosts...)
 	if err != nil {
 		fmt.Fprintf(stderr, "URL解析错误: %v\n", err)
 		return 1
 	}
 
-	// 3. 创建GitHub客户端
-	client := github.NewClient(cfg.Token)
+	// 4. 创建GitHub客户端和转换器
+	clientOpts := []github.Option{
+		github.WithHost(resource.Host),
+		github.WithRateLimitWait(cfg.RateLimitWait),
+		github.WithTimelineEvents(cfg.EnableEvents),
+		github.WithEditHistory(cfg.EnableEditHistory),
+	}
+	if cfg.Verbose {
+		clientOpts = append(clientOpts, github.WithLogger(log.New(stderr, "issue2md: ", 0)))
+	}
+	if cfg.EnableCache {
+		clientOpts = append(clientOpts, github.WithCache(cache))
+	}
+	client := github.NewClient(cfg.TokenFor(resource.Host), clientOpts...)
+	conv := converter.NewConverter(
+		converter.WithReactions(cfg.EnableReactions),
+		converter.WithUserLinks(cfg.EnableUserLinks),
+		converter.WithHideResolvedThreads(cfg.HideResolvedThreads),
+		converter.WithHideOutdatedThreads(cfg.HideOutdatedThreads),
+		converter.WithPatches(cfg.EnablePatches),
+		converter.WithPatchSizeLimit(cfg.PatchMaxBytes),
+		converter.WithTimelineEvents(cfg.EnableEvents),
+		converter.WithEditHistory(cfg.EnableEditHistory),
+		converter.WithMinimizedPolicy(converter.MinimizedPolicy(cfg.Minimized)),
+		converter.WithAuthorBadges(cfg.EnableAuthorBadges),
+		converter.WithMaintainersOnly(cfg.MaintainersOnly),
+	)
 
-	// 4. 根据资源类型获取数据
+	// 5. 根据资源类型获取数据
 	var markdown string
 	var fetchErr error
 
 	switch resource.Type {
 	case parser.Issue:
-		issue, err := client.FetchIssue(resource.Owner, resource.Repo, resource.Number)
+		issue, err := client.FetchIssueContext(ctx, resource.Owner, resource.Repo, resource.Number)
 		if err != nil {
 			fetchErr = err
 			break
 		}
-		conv := converter.NewConverter(
-			converter.WithReactions(cfg.EnableReactions),
-			converter.WithUserLinks(cfg.EnableUserLinks),
-		)
 		markdown, err = conv.ConvertIssue(issue)
 		if err != nil {
 			fetchErr = err
 		}
 	case parser.PullRequest:
-		pr, err := client.FetchPullRequest(resource.Owner, resource.Repo, resource.Number)
+		pr, err := client.FetchPullRequestContext(ctx, resource.Owner, resource.Repo, resource.Number)
 		if err != nil {
 			fetchErr = err
 			break
 		}
-		conv := converter.NewConverter(
-			converter.WithReactions(cfg.EnableReactions),
-			converter.WithUserLinks(cfg.EnableUserLinks),
-		)
 		markdown, err = conv.ConvertPullRequest(pr)
 		if err != nil {
 			fetchErr = err
 		}
 	case parser.Discussion:
-		discussion, err := client.FetchDiscussion(resource.Owner, resource.Repo, resource.Number)
+		discussion, err := client.FetchDiscussionContext(ctx, resource.Owner, resource.Repo, resource.Number)
 		if err != nil {
 			fetchErr = err
 			break
 		}
-		conv := converter.NewConverter(
-			converter.WithReactions(cfg.EnableReactions),
-			converter.WithUserLinks(cfg.EnableUserLinks),
-		)
 		markdown, err = conv.ConvertDiscussion(discussion)
 		if err != nil {
 			fetchErr = err
@@ -86,13 +130,17 @@ func Run(argv []string, stdout, stderr io.Writer) int {
 		return 1
 	}
 
-	// 5. 处理获取/转换错误
+	// 6. 处理获取/转换错误
+	if errors.Is(fetchErr, context.Canceled) {
+		fmt.Fprintln(stderr, "操作已取消")
+		return 1
+	}
 	if fetchErr != nil {
 		fmt.Fprintf(stderr, "GitHub API错误: %v\n", fetchErr)
 		return 1
 	}
 
-	// 6. 输出结果
+	// 7. 输出结果
 	if cfg.OutputFile == "" {
 		// 输出到stdout
 		fmt.Fprint(stdout, markdown)
@@ -107,3 +155,20 @@ func Run(argv []string, stdout, stderr io.Writer) int {
 
 	return 0
 }
+
+// newCache 根据配置创建磁盘缓存，既未启用缓存也不清空缓存时返回 nil
+func newCache(cfg *config.Config) (*github.Cache, error) {
+	if !cfg.EnableCache && !cfg.ClearCache {
+		return nil, nil
+	}
+
+	dir := cfg.CacheDir
+	if dir == "" {
+		defaultDir, err := github.DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = defaultDir
+	}
+	return github.NewCache(dir, cfg.CacheTTL), nil
+}
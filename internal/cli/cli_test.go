@@ -2,6 +2,9 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -118,3 +121,71 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// TestRunContext_Canceled 测试 ctx 取消后中止请求并提示已取消
+func TestRunContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := RunContext(ctx, []string{"https://github.com/owner/repo/issues/1"}, stdout, stderr)
+
+	if exitCode != 1 {
+		t.Errorf("RunContext() exitCode = %d, want 1", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("RunContext() stdout = %q, want empty", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "已取消") {
+		t.Errorf("RunContext() stderr = %q, want to contain 已取消", stderr.String())
+	}
+}
+
+// TestRun_ClearCache 测试 -clear-cache 未提供 URL 时清空缓存后退出，且只删除缓存自身的文件
+func TestRun_ClearCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	if err := os.MkdirAll(filepath.Join(dir, "notes"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]bool{
+		strings.Repeat("ab", 32) + ".json": true,  // 缓存条目
+		"entry-123.tmp":                    true,  // 残留的临时文件
+		"settings.json":                    false, // 无关文件
+		"notes.txt":                        false,
+		"notes/" + strings.Repeat("cd", 32) + ".json": false, // 子目录中的文件
+	}
+	for name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := Run([]string{"-clear-cache", "-cache-dir", dir}, stdout, stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("Run() exitCode = %d, want 0 (stderr: %s)", exitCode, stderr.String())
+	}
+	for name, removed := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if removed && !os.IsNotExist(err) {
+			t.Errorf("expected cache file %s to be removed, got %v", name, err)
+		}
+		if !removed && err != nil {
+			t.Errorf("unrelated file %s should survive -clear-cache, got %v", name, err)
+		}
+	}
+	if !strings.Contains(stdout.String(), dir) {
+		t.Errorf("Run() stdout = %q, want to contain cache dir", stdout.String())
+	}
+
+	// 目录不存在时清空缓存不报错
+	exitCode = Run([]string{"-clear-cache", "-cache-dir", filepath.Join(dir, "missing")}, stdout, stderr)
+	if exitCode != 0 {
+		t.Errorf("Run() on missing dir exitCode = %d, want 0 (stderr: %s)", exitCode, stderr.String())
+	}
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"strings"
+	"time"
+)
+
 // Config 应用配置
 type Config struct {
 	// 输入
@@ -9,9 +14,41 @@ type Config struct {
 	OutputFile string // 空字符串表示stdout
 
 	// 功能开关
-	EnableReactions bool
-	EnableUserLinks bool
+	EnableReactions     bool
+	EnableUserLinks     bool
+	HideResolvedThreads bool   // 隐藏已解决的 PR 代码会话
+	HideOutdatedThreads bool   // 隐藏过时的 PR 代码会话
+	EnablePatches       bool   // 在 PR 末尾附加补丁
+	PatchMaxBytes       int    // 单个文件补丁的最大字节数，0 表示不限制
+	EnableEvents        bool   // 在 Issue 评论之间显示时间线事件
+	EnableEditHistory   bool   // 获取并显示正文和评论的编辑历史
+	Minimized           string // 被隐藏评论的处理方式：show、collapse、omit
+	EnableAuthorBadges  bool   // 标注作者与仓库的关系（所有者、成员等）
+	MaintainersOnly     bool   // 只输出维护者的评论
+
+	// 网络
+	RateLimitWait time.Duration // 触发限流时自动等待的最长时间，0 表示不等待
+	Verbose       bool          // 输出重试、限流等待等详细日志到 stderr
+
+	// 缓存
+	EnableCache bool          // 启用磁盘 HTTP 缓存
+	CacheDir    string        // 缓存目录，空字符串表示使用默认目录
+	CacheTTL    time.Duration // 缓存条目免校验的有效期，0 表示总是发送条件请求
+	ClearCache  bool          // 运行前清空缓存
+
+	// GitHub Enterprise Server
+	Hosts []string // 额外允许的 GHES 主机名（github.com 始终允许）
 
 	// 认证
-	Token string // 从环境变量GITHUB_TOKEN读取
+	Token      string            // github.com 的 Token，从环境变量GITHUB_TOKEN读取
+	HostTokens map[string]string // 各 GHES 主机的 Token，键为小写主机名
+}
+
+// TokenFor 返回访问指定主机使用的 Token
+func (c *Config) TokenFor(host string) string {
+	host = strings.ToLower(host)
+	if host == "" || host == "github.com" {
+		return c.Token
+	}
+	return c.HostTokens[host]
 }
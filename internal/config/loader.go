@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 )
 
 // LoadFromFlags 从命令行参数和环境变量加载配置
@@ -22,12 +24,44 @@ func LoadFromFlags(argv []string, stdout, stderr io.Writer) (*Config, int) {
 	// 定义 flag 变量
 	var enableReactions bool
 	var enableUserLinks bool
+	var hideResolved bool
+	var hideOutdated bool
+	var enablePatches bool
+	var patchMaxBytes int
+	var enableEvents bool
+	var enableEditHistory bool
+	var minimized string
+	var enableAuthorBadges bool
+	var maintainersOnly bool
+	var rateLimitWait time.Duration
+	var verbose bool
+	var enableCache bool
+	var cacheDir string
+	var cacheTTL time.Duration
+	var clearCache bool
+	var hosts string
 	var showVersion bool
 	var showHelp bool
 
 	// 注册 flag
 	fs.BoolVar(&enableReactions, "enable-reactions", false, "显示 reactions 统计")
 	fs.BoolVar(&enableUserLinks, "enable-user-links", false, "用户名显示为可点击链接")
+	fs.BoolVar(&hideResolved, "hide-resolved", false, "隐藏已解决的 PR 代码会话")
+	fs.BoolVar(&hideOutdated, "hide-outdated", false, "隐藏过时的 PR 代码会话")
+	fs.BoolVar(&enablePatches, "enable-patches", false, "在 PR 末尾附加各文件的补丁")
+	fs.IntVar(&patchMaxBytes, "patch-max-bytes", 20000, "单个文件补丁的最大字节数（0 表示不限制）")
+	fs.BoolVar(&enableEvents, "enable-events", false, "在 Issue 评论之间显示时间线事件（标签、指派、关闭等）")
+	fs.BoolVar(&enableEditHistory, "edit-history", false, "以可折叠 diff 显示正文和评论的编辑历史")
+	fs.StringVar(&minimized, "minimized", "collapse", "被隐藏评论的处理方式：show、collapse 或 omit")
+	fs.BoolVar(&enableAuthorBadges, "author-badges", false, "标注作者与仓库的关系（所有者、成员、协作者等）")
+	fs.BoolVar(&maintainersOnly, "maintainers-only", false, "只输出维护者（所有者、成员、协作者）的评论")
+	fs.DurationVar(&rateLimitWait, "rate-limit-wait", 0, "触发限流时自动等待的最长时间（如 15m，0 表示不等待）")
+	fs.BoolVar(&verbose, "verbose", false, "输出详细日志（重试、限流等待等）")
+	fs.BoolVar(&enableCache, "cache", false, "启用磁盘 HTTP 缓存（条件请求，304 不计入限额）")
+	fs.StringVar(&cacheDir, "cache-dir", "", "缓存目录（设置后自动启用缓存）")
+	fs.DurationVar(&cacheTTL, "cache-ttl", 0, "缓存条目免校验的有效期（如 10m，0 表示总是校验）")
+	fs.BoolVar(&clearCache, "clear-cache", false, "运行前清空缓存（未提供 URL 时清空后退出）")
+	fs.StringVar(&hosts, "hosts", "", "额外允许的 GitHub Enterprise Server 主机名，逗号分隔")
 	fs.BoolVar(&showVersion, "version", false, "显示版本信息")
 	fs.BoolVar(&showHelp, "help", false, "显示帮助信息")
 
@@ -53,18 +87,29 @@ func LoadFromFlags(argv []string, stdout, stderr io.Writer) (*Config, int) {
 		return nil, 0
 	}
 
+	// 校验被隐藏评论的处理方式
+	switch minimized {
+	case "show", "collapse", "omit":
+	default:
+		fmt.Fprintf(stderr, "错误: -minimized 必须是 show、collapse 或 omit，实际为 %q\n", minimized)
+		return nil, 1
+	}
+
 	// 获取位置参数
 	args := fs.Args()
 
-	// 检查是否提供了 URL 参数
-	if len(args) == 0 {
+	// 检查是否提供了 URL 参数（仅清空缓存时可以省略）
+	if len(args) == 0 && !clearCache {
 		fmt.Fprintln(stderr, "错误: 缺少必需参数 URL")
 		fmt.Fprintln(stderr, "使用 --help 查看使用说明")
 		return nil, 1
 	}
 
 	// 第一个位置参数是 URL
-	url := args[0]
+	url := ""
+	if len(args) > 0 {
+		url = args[0]
+	}
 
 	// 第二个位置参数（可选）是输出文件
 	outputFile := ""
@@ -72,21 +117,76 @@ func LoadFromFlags(argv []string, stdout, stderr io.Writer) (*Config, int) {
 		outputFile = args[1]
 	}
 
-	// 从环境变量读取 GitHub Token
+	// 从环境变量读取 GitHub Token（github.com 和各 GHES 主机分别读取）
 	token := os.Getenv("GITHUB_TOKEN")
+	hostList := splitHosts(hosts)
+	hostTokens := make(map[string]string, len(hostList))
+	for _, host := range hostList {
+		if hostToken := enterpriseToken(host); hostToken != "" {
+			hostTokens[host] = hostToken
+		}
+	}
 
 	// 构建配置
 	cfg := &Config{
-		URL:             url,
-		OutputFile:      outputFile,
-		EnableReactions: enableReactions,
-		EnableUserLinks: enableUserLinks,
-		Token:           token,
+		URL:                 url,
+		OutputFile:          outputFile,
+		EnableReactions:     enableReactions,
+		EnableUserLinks:     enableUserLinks,
+		HideResolvedThreads: hideResolved,
+		HideOutdatedThreads: hideOutdated,
+		EnablePatches:       enablePatches,
+		PatchMaxBytes:       patchMaxBytes,
+		EnableEvents:        enableEvents,
+		EnableEditHistory:   enableEditHistory,
+		Minimized:           minimized,
+		EnableAuthorBadges:  enableAuthorBadges,
+		MaintainersOnly:     maintainersOnly,
+		RateLimitWait:       rateLimitWait,
+		Verbose:             verbose,
+		EnableCache:         enableCache || cacheDir != "",
+		CacheDir:            cacheDir,
+		CacheTTL:            cacheTTL,
+		ClearCache:          clearCache,
+		Hosts:               hostList,
+		HostTokens:          hostTokens,
+		Token:               token,
 	}
 
 	return cfg, -1
 }
 
+// splitHosts 解析逗号分隔的主机名列表（去除空白、转为小写、忽略空项）
+func splitHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// enterpriseToken 读取 GHES 主机的 Token
+// 优先读取 GITHUB_TOKEN_<HOST>（主机名转大写，非字母数字替换为下划线），其次是 GH_ENTERPRISE_TOKEN
+func enterpriseToken(host string) string {
+	if token := os.Getenv(hostTokenEnv(host)); token != "" {
+		return token
+	}
+	return os.Getenv("GH_ENTERPRISE_TOKEN")
+}
+
+// hostTokenEnv 返回主机专用 Token 的环境变量名，如 ghe.example.com → GITHUB_TOKEN_GHE_EXAMPLE_COM
+func hostTokenEnv(host string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(host))
+	return "GITHUB_TOKEN_" + name
+}
+
 // printHelp 输出帮助信息
 func printHelp(w io.Writer) {
 	fmt.Fprintln(w, "issue2md - 将 GitHub Issue/PR/Discussion 转换为 Markdown")
@@ -101,11 +201,29 @@ func printHelp(w io.Writer) {
 	fmt.Fprintln(w, "Flags:")
 	fmt.Fprintln(w, "  -enable-reactions   显示 reactions 统计（如 👍 3 ❤️ 1）")
 	fmt.Fprintln(w, "  -enable-user-links  用户名显示为可点击链接")
+	fmt.Fprintln(w, "  -hide-resolved      隐藏已解决的 PR 代码会话（需要 GITHUB_TOKEN）")
+	fmt.Fprintln(w, "  -hide-outdated      隐藏过时的 PR 代码会话（需要 GITHUB_TOKEN）")
+	fmt.Fprintln(w, "  -enable-patches     在 PR 末尾附加各文件的补丁")
+	fmt.Fprintln(w, "  -patch-max-bytes    单个文件补丁的最大字节数（默认 20000，0 表示不限制）")
+	fmt.Fprintln(w, "  -enable-events      在 Issue 评论之间显示时间线事件（标签、指派、关闭等）")
+	fmt.Fprintln(w, "  -edit-history       以可折叠 diff 显示正文和评论的编辑历史（需要 GITHUB_TOKEN）")
+	fmt.Fprintln(w, "  -minimized          被隐藏评论的处理方式：show 原样显示、collapse 折叠（默认）、omit 省略")
+	fmt.Fprintln(w, "  -author-badges      在作者和评论标题中标注作者与仓库的关系（所有者、成员、协作者等）")
+	fmt.Fprintln(w, "  -maintainers-only   只输出维护者的评论（回复中有维护者发言的评论保留作为上下文）")
+	fmt.Fprintln(w, "  -rate-limit-wait    触发限流时自动等待的最长时间（如 15m，默认 0 表示不等待）")
+	fmt.Fprintln(w, "  -verbose            输出详细日志到 stderr（重试、限流等待等）")
+	fmt.Fprintln(w, "  -cache              启用磁盘 HTTP 缓存（条件请求，304 不计入限额）")
+	fmt.Fprintln(w, "  -cache-dir          缓存目录（默认为用户缓存目录下的 issue2md，设置后自动启用缓存）")
+	fmt.Fprintln(w, "  -cache-ttl          缓存条目免校验的有效期（如 10m，默认 0 表示总是校验）")
+	fmt.Fprintln(w, "  -clear-cache        运行前清空缓存（未提供 URL 时清空后退出）")
+	fmt.Fprintln(w, "  -hosts              额外允许的 GitHub Enterprise Server 主机名，逗号分隔")
 	fmt.Fprintln(w, "  -version            显示版本信息")
 	fmt.Fprintln(w, "  -help               显示此帮助信息")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Environment Variables:")
-	fmt.Fprintln(w, "  GITHUB_TOKEN  GitHub Personal Access Token（可选）")
+	fmt.Fprintln(w, "  GITHUB_TOKEN         GitHub Personal Access Token（可选）")
+	fmt.Fprintln(w, "  GITHUB_TOKEN_<HOST>  指定 GHES 主机的 Token，如 GITHUB_TOKEN_GHE_EXAMPLE_COM")
+	fmt.Fprintln(w, "  GH_ENTERPRISE_TOKEN  所有 GHES 主机的默认 Token")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Examples:")
 	fmt.Fprintln(w, "  issue2md https://github.com/owner/repo/issues/123")
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestLoadFromFlags_ValidURL 测试基本的URL参数解析
@@ -109,6 +110,346 @@ func TestLoadFromFlags_EnableUserLinksFlag(t *testing.T) {
 	}
 }
 
+// TestLoadFromFlags_ThreadFlags 测试 --hide-resolved 和 --hide-outdated flag
+func TestLoadFromFlags_ThreadFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		wantHideResolved bool
+		wantHideOutdated bool
+	}{
+		{
+			name:             "no thread flags",
+			args:             []string{"https://github.com/owner/repo/pull/1"},
+			wantHideResolved: false,
+			wantHideOutdated: false,
+		},
+		{
+			name:             "hide resolved",
+			args:             []string{"-hide-resolved", "https://github.com/owner/repo/pull/1"},
+			wantHideResolved: true,
+			wantHideOutdated: false,
+		},
+		{
+			name:             "hide resolved and outdated",
+			args:             []string{"-hide-resolved", "-hide-outdated", "https://github.com/owner/repo/pull/1"},
+			wantHideResolved: true,
+			wantHideOutdated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			cfg, exitCode := LoadFromFlags(tt.args, stdout, stderr)
+
+			if exitCode != -1 {
+				t.Fatalf("expected exitCode -1, got %d", exitCode)
+			}
+
+			if cfg.HideResolvedThreads != tt.wantHideResolved {
+				t.Errorf("expected HideResolvedThreads to be %v, got %v", tt.wantHideResolved, cfg.HideResolvedThreads)
+			}
+
+			if cfg.HideOutdatedThreads != tt.wantHideOutdated {
+				t.Errorf("expected HideOutdatedThreads to be %v, got %v", tt.wantHideOutdated, cfg.HideOutdatedThreads)
+			}
+		})
+	}
+}
+
+// TestLoadFromFlags_PatchFlags 测试 --enable-patches 和 --patch-max-bytes flag
+func TestLoadFromFlags_PatchFlags(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		wantEnablePatches bool
+		wantPatchMaxBytes int
+	}{
+		{
+			name:              "defaults",
+			args:              []string{"https://github.com/owner/repo/pull/1"},
+			wantEnablePatches: false,
+			wantPatchMaxBytes: 20000,
+		},
+		{
+			name:              "enable patches with custom limit",
+			args:              []string{"-enable-patches", "-patch-max-bytes", "500", "https://github.com/owner/repo/pull/1"},
+			wantEnablePatches: true,
+			wantPatchMaxBytes: 500,
+		},
+		{
+			name:              "unlimited patches",
+			args:              []string{"-enable-patches", "-patch-max-bytes=0", "https://github.com/owner/repo/pull/1"},
+			wantEnablePatches: true,
+			wantPatchMaxBytes: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			cfg, exitCode := LoadFromFlags(tt.args, stdout, stderr)
+
+			if exitCode != -1 {
+				t.Fatalf("expected exitCode -1, got %d", exitCode)
+			}
+
+			if cfg.EnablePatches != tt.wantEnablePatches {
+				t.Errorf("expected EnablePatches to be %v, got %v", tt.wantEnablePatches, cfg.EnablePatches)
+			}
+
+			if cfg.PatchMaxBytes != tt.wantPatchMaxBytes {
+				t.Errorf("expected PatchMaxBytes to be %d, got %d", tt.wantPatchMaxBytes, cfg.PatchMaxBytes)
+			}
+		})
+	}
+}
+
+// TestLoadFromFlags_RateLimitWait 测试 --rate-limit-wait flag
+func TestLoadFromFlags_RateLimitWait(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want time.Duration
+	}{
+		{
+			name: "default does not wait",
+			args: []string{"https://github.com/owner/repo/issues/1"},
+			want: 0,
+		},
+		{
+			name: "custom wait",
+			args: []string{"-rate-limit-wait", "15m", "https://github.com/owner/repo/issues/1"},
+			want: 15 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			cfg, exitCode := LoadFromFlags(tt.args, stdout, stderr)
+
+			if exitCode != -1 {
+				t.Fatalf("expected exitCode -1, got %d", exitCode)
+			}
+
+			if cfg.RateLimitWait != tt.want {
+				t.Errorf("expected RateLimitWait to be %v, got %v", tt.want, cfg.RateLimitWait)
+			}
+		})
+	}
+}
+
+// TestLoadFromFlags_Verbose 测试 --verbose flag
+func TestLoadFromFlags_Verbose(t *testing.T) {
+	for _, args := range [][]string{
+		{"https://github.com/owner/repo/issues/1"},
+		{"-verbose", "https://github.com/owner/repo/issues/1"},
+	} {
+		cfg, exitCode := LoadFromFlags(args, &bytes.Buffer{}, &bytes.Buffer{})
+		if exitCode != -1 {
+			t.Fatalf("expected exitCode -1, got %d", exitCode)
+		}
+
+		want := len(args) == 2
+		if cfg.Verbose != want {
+			t.Errorf("args %v: expected Verbose to be %v, got %v", args, want, cfg.Verbose)
+		}
+	}
+}
+
+// TestLoadFromFlags_CacheFlags 测试缓存相关 flag
+func TestLoadFromFlags_CacheFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		wantExitCode    int
+		wantEnableCache bool
+		wantCacheDir    string
+		wantCacheTTL    time.Duration
+		wantClearCache  bool
+	}{
+		{
+			name:         "cache disabled by default",
+			args:         []string{"https://github.com/owner/repo/issues/1"},
+			wantExitCode: -1,
+		},
+		{
+			name:            "enable cache with ttl",
+			args:            []string{"-cache", "-cache-ttl", "10m", "https://github.com/owner/repo/issues/1"},
+			wantExitCode:    -1,
+			wantEnableCache: true,
+			wantCacheTTL:    10 * time.Minute,
+		},
+		{
+			name:            "cache dir implies cache",
+			args:            []string{"-cache-dir", "/tmp/issue2md-cache", "https://github.com/owner/repo/issues/1"},
+			wantExitCode:    -1,
+			wantEnableCache: true,
+			wantCacheDir:    "/tmp/issue2md-cache",
+		},
+		{
+			name:           "clear cache without URL",
+			args:           []string{"-clear-cache"},
+			wantExitCode:   -1,
+			wantClearCache: true,
+		},
+		{
+			name:         "URL still required without clear cache",
+			args:         []string{"-cache"},
+			wantExitCode: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, exitCode := LoadFromFlags(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+
+			if exitCode != tt.wantExitCode {
+				t.Fatalf("expected exitCode %d, got %d", tt.wantExitCode, exitCode)
+			}
+			if exitCode != -1 {
+				return
+			}
+
+			if cfg.EnableCache != tt.wantEnableCache || cfg.CacheDir != tt.wantCacheDir ||
+				cfg.CacheTTL != tt.wantCacheTTL || cfg.ClearCache != tt.wantClearCache {
+				t.Errorf("unexpected cache config: %+v", cfg)
+			}
+		})
+	}
+}
+
+// TestLoadFromFlags_Hosts 测试 --hosts flag 和各主机的 Token
+func TestLoadFromFlags_Hosts(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "dotcom-token")
+	t.Setenv("GITHUB_TOKEN_GHE_EXAMPLE_COM", "ghe-token")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "fallback-token")
+
+	args := []string{"-hosts", " GHE.example.com, other.example.org ,", "https://ghe.example.com/o/r/issues/1"}
+	cfg, exitCode := LoadFromFlags(args, &bytes.Buffer{}, &bytes.Buffer{})
+	if exitCode != -1 {
+		t.Fatalf("expected exitCode -1, got %d", exitCode)
+	}
+
+	if len(cfg.Hosts) != 2 || cfg.Hosts[0] != "ghe.example.com" || cfg.Hosts[1] != "other.example.org" {
+		t.Errorf("unexpected hosts: %v", cfg.Hosts)
+	}
+
+	tests := map[string]string{
+		"github.com":        "dotcom-token",
+		"ghe.example.com":   "ghe-token",
+		"GHE.EXAMPLE.COM":   "ghe-token",
+		"other.example.org": "fallback-token",
+		"unknown.example":   "",
+	}
+	for host, want := range tests {
+		if got := cfg.TokenFor(host); got != want {
+			t.Errorf("TokenFor(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+// TestLoadFromFlags_EnableEvents 测试 --enable-events flag
+func TestLoadFromFlags_EnableEvents(t *testing.T) {
+	for _, args := range [][]string{
+		{"https://github.com/owner/repo/issues/1"},
+		{"-enable-events", "https://github.com/owner/repo/issues/1"},
+	} {
+		cfg, exitCode := LoadFromFlags(args, &bytes.Buffer{}, &bytes.Buffer{})
+		if exitCode != -1 {
+			t.Fatalf("expected exitCode -1, got %d", exitCode)
+		}
+
+		want := len(args) == 2
+		if cfg.EnableEvents != want {
+			t.Errorf("args %v: expected EnableEvents to be %v, got %v", args, want, cfg.EnableEvents)
+		}
+	}
+}
+
+// TestLoadFromFlags_EditHistory 测试 --edit-history flag
+func TestLoadFromFlags_EditHistory(t *testing.T) {
+	for _, args := range [][]string{
+		{"https://github.com/owner/repo/issues/1"},
+		{"-edit-history", "https://github.com/owner/repo/issues/1"},
+	} {
+		cfg, exitCode := LoadFromFlags(args, &bytes.Buffer{}, &bytes.Buffer{})
+		if exitCode != -1 {
+			t.Fatalf("expected exitCode -1, got %d", exitCode)
+		}
+
+		want := len(args) == 2
+		if cfg.EnableEditHistory != want {
+			t.Errorf("args %v: expected EnableEditHistory to be %v, got %v", args, want, cfg.EnableEditHistory)
+		}
+	}
+}
+
+// TestLoadFromFlags_Minimized 测试 --minimized flag
+func TestLoadFromFlags_Minimized(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantExit int
+	}{
+		{name: "default", args: []string{"https://github.com/owner/repo/issues/1"}, want: "collapse", wantExit: -1},
+		{name: "omit", args: []string{"-minimized", "omit", "https://github.com/owner/repo/issues/1"}, want: "omit", wantExit: -1},
+		{name: "show", args: []string{"-minimized=show", "https://github.com/owner/repo/issues/1"}, want: "show", wantExit: -1},
+		{name: "invalid", args: []string{"-minimized", "hide", "https://github.com/owner/repo/issues/1"}, wantExit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			cfg, exitCode := LoadFromFlags(tt.args, &bytes.Buffer{}, stderr)
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exitCode %d, got %d (stderr: %s)", tt.wantExit, exitCode, stderr.String())
+			}
+			if tt.wantExit != -1 {
+				if !strings.Contains(stderr.String(), "-minimized") {
+					t.Errorf("expected error about -minimized, got %q", stderr.String())
+				}
+				return
+			}
+			if cfg.Minimized != tt.want {
+				t.Errorf("expected Minimized %q, got %q", tt.want, cfg.Minimized)
+			}
+		})
+	}
+}
+
+// TestLoadFromFlags_AuthorFlags 测试 --author-badges 和 --maintainers-only flag
+func TestLoadFromFlags_AuthorFlags(t *testing.T) {
+	tests := []struct {
+		args            []string
+		wantBadges      bool
+		wantMaintainers bool
+	}{
+		{args: []string{"https://github.com/owner/repo/issues/1"}},
+		{args: []string{"-author-badges", "https://github.com/owner/repo/issues/1"}, wantBadges: true},
+		{args: []string{"-maintainers-only", "https://github.com/owner/repo/issues/1"}, wantMaintainers: true},
+	}
+
+	for _, tt := range tests {
+		cfg, exitCode := LoadFromFlags(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if exitCode != -1 {
+			t.Fatalf("expected exitCode -1, got %d", exitCode)
+		}
+		if cfg.EnableAuthorBadges != tt.wantBadges || cfg.MaintainersOnly != tt.wantMaintainers {
+			t.Errorf("args %v: got EnableAuthorBadges=%v MaintainersOnly=%v", tt.args, cfg.EnableAuthorBadges, cfg.MaintainersOnly)
+		}
+	}
+}
+
 // TestLoadFromFlags_BothFlags 测试同时启用两个flag
 func TestLoadFromFlags_BothFlags(t *testing.T) {
 	stdout := &bytes.Buffer{}
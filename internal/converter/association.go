@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// associationLabels 作者与仓库关系的标注文本（NONE 不标注）
+var associationLabels = map[github.AuthorAssociation]string{
+	github.AssociationOwner:                "所有者",
+	github.AssociationMember:               "成员",
+	github.AssociationCollaborator:         "协作者",
+	github.AssociationContributor:          "贡献者",
+	github.AssociationFirstTimeContributor: "首次贡献者",
+	github.AssociationFirstTimer:           "GitHub 新用户",
+	github.AssociationMannequin:            "占位账号",
+}
+
+// formatAuthor 格式化作者，启用作者标注时附加其与仓库的关系
+func (c *Converter) formatAuthor(user github.User, association github.AuthorAssociation) string {
+	author := c.formatUser(user)
+	if !c.enableAuthorBadges {
+		return author
+	}
+	if label, ok := associationLabels[association]; ok {
+		author += fmt.Sprintf(" [%s]", label)
+	}
+	return author
+}
+
+// associationFrontmatter 返回作者关系的 Frontmatter 字段（未知时为空）
+func (c *Converter) associationFrontmatter(association github.AuthorAssociation) []frontmatterField {
+	if association == "" {
+		return nil
+	}
+	return []frontmatterField{{key: "author_association", value: string(association)}}
+}
+
+// hasMaintainer 判断评论或其任一回复是否由维护者发表
+func hasMaintainer(comment github.Comment) bool {
+	if comment.AuthorAssociation.IsMaintainer() {
+		return true
+	}
+	for _, reply := range comment.Replies {
+		if hasMaintainer(reply) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipComment 判断评论是否不输出：按隐藏策略省略的评论，
+// 以及仅显示维护者时与维护者无关的评论（回复中有维护者发言的评论保留作为上下文）
+func (c *Converter) skipComment(comment github.Comment) bool {
+	if comment.Minimized && c.minimizedPolicy == MinimizedOmit {
+		return true
+	}
+	return c.maintainersOnly && !hasMaintainer(comment)
+}
@@ -2,7 +2,6 @@ package converter
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -42,8 +41,17 @@ var emojiMap = map[string]string{
 
 // Converter Markdown转换器
 type Converter struct {
-	enableReactions bool
-	enableUserLinks bool
+	enableReactions     bool
+	enableUserLinks     bool
+	hideResolvedThreads bool
+	hideOutdatedThreads bool
+	enablePatches       bool
+	patchSizeLimit      int // 单个文件补丁的最大字节数，0 表示不限制
+	enableEvents        bool
+	enableEditHistory   bool
+	minimizedPolicy     MinimizedPolicy
+	enableAuthorBadges  bool
+	maintainersOnly     bool
 }
 
 // Option 配置选项类型（函数式选项模式）
@@ -63,11 +71,75 @@ func WithUserLinks(enable bool) Option {
 	}
 }
 
+// WithHideResolvedThreads 隐藏已解决的 PR 代码会话
+func WithHideResolvedThreads(hide bool) Option {
+	return func(c *Converter) {
+		c.hideResolvedThreads = hide
+	}
+}
+
+// WithHideOutdatedThreads 隐藏代码已变更（过时）的 PR 代码会话
+func WithHideOutdatedThreads(hide bool) Option {
+	return func(c *Converter) {
+		c.hideOutdatedThreads = hide
+	}
+}
+
+// WithPatches 在 PR 末尾附加各文件的补丁
+func WithPatches(enable bool) Option {
+	return func(c *Converter) {
+		c.enablePatches = enable
+	}
+}
+
+// WithPatchSizeLimit 设置单个文件补丁的最大字节数，超出部分截断（0 表示不限制）
+func WithPatchSizeLimit(limit int) Option {
+	return func(c *Converter) {
+		c.patchSizeLimit = limit
+	}
+}
+
+// WithTimelineEvents 在 Issue 评论之间按时间顺序插入时间线事件
+func WithTimelineEvents(enable bool) Option {
+	return func(c *Converter) {
+		c.enableEvents = enable
+	}
+}
+
+// WithEditHistory 在正文和评论下方以可折叠区块显示编辑历史
+func WithEditHistory(enable bool) Option {
+	return func(c *Converter) {
+		c.enableEditHistory = enable
+	}
+}
+
+// WithMinimizedPolicy 设置被隐藏评论的处理方式（默认折叠）
+func WithMinimizedPolicy(policy MinimizedPolicy) Option {
+	return func(c *Converter) {
+		c.minimizedPolicy = policy
+	}
+}
+
+// WithAuthorBadges 在作者和评论标题中标注作者与仓库的关系（所有者、成员、协作者等）
+func WithAuthorBadges(enable bool) Option {
+	return func(c *Converter) {
+		c.enableAuthorBadges = enable
+	}
+}
+
+// WithMaintainersOnly 只输出维护者（所有者、成员、协作者）的评论
+func WithMaintainersOnly(enable bool) Option {
+	return func(c *Converter) {
+		c.maintainersOnly = enable
+	}
+}
+
 // NewConverter 创建新的Converter
 func NewConverter(options ...Option) *Converter {
 	c := &Converter{
 		enableReactions: false,
 		enableUserLinks: false,
+		minimizedPolicy: MinimizedCollapse,
 	}
 
 	for _, opt := range options {
@@ -77,18 +149,71 @@ func NewConverter(options ...Option) *Converter {
 	return c
 }
 
-// formatYAMLFrontmatter 格式化 YAML Frontmatter
-func (c *Converter) formatYAMLFrontmatter(title, url, author, createdAt, status string) string {
-	return fmt.Sprintf("---\ntitle: %q\nurl: %q\nauthor: %q\ncreated_at: %q\nstatus: %q\n---\n\n",
-		title, url, author, createdAt, status)
+// frontmatterField YAML Frontmatter 中的附加字段
+// value 支持 string（加引号输出）、[]string（YAML 列表）、bool 和 int
+type frontmatterField struct {
+	key   string
+	value interface{}
+}
+
+// concatFrontmatter 按顺序合并多组 Frontmatter 字段
+func concatFrontmatter(groups ...[]frontmatterField) []frontmatterField {
+	fields := []frontmatterField{}
+	for _, group := range groups {
+		fields = append(fields, group...)
+	}
+	return fields
 }
 
-// formatUser 格式化用户名
+// formatYAMLFrontmatter 格式化 YAML Frontmatter，附加字段按传入顺序输出在 status 之后
+func (c *Converter) formatYAMLFrontmatter(title, url, author, createdAt, status string, extra ...frontmatterField) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("---\ntitle: %q\nurl: %q\nauthor: %q\ncreated_at: %q\nstatus: %q\n",
+		title, url, author, createdAt, status))
+
+	for _, field := range extra {
+		switch v := field.value.(type) {
+		case string:
+			builder.WriteString(fmt.Sprintf("%s: %q\n", field.key, v))
+		case []string:
+			if len(v) == 0 {
+				builder.WriteString(fmt.Sprintf("%s: []\n", field.key))
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("%s:\n", field.key))
+			for _, item := range v {
+				builder.WriteString(fmt.Sprintf("  - %q\n", item))
+			}
+		default:
+			builder.WriteString(fmt.Sprintf("%s: %v\n", field.key, v))
+		}
+	}
+
+	builder.WriteString("---\n\n")
+	return builder.String()
+}
+
+// userKindBadges 非普通账号在用户名后附加的标注
+var userKindBadges = map[github.UserKind]string{
+	github.UserKindBot:       "bot",
+	github.UserKindGhost:     "已删除",
+	github.UserKindMannequin: "占位账号",
+}
+
+// formatUser 格式化用户名，机器人、已删除和占位账号附加标注，用户名缺失时显示“未知用户”
 func (c *Converter) formatUser(user github.User) string {
-	if c.enableUserLinks {
-		return fmt.Sprintf("[@%s](%s)", user.Login, user.HTMLURL)
+	if user.Login == "" {
+		return "未知用户"
+	}
+
+	name := fmt.Sprintf("@%s", user.Login)
+	if c.enableUserLinks && user.HTMLURL != "" {
+		name = fmt.Sprintf("[@%s](%s)", user.Login, user.HTMLURL)
 	}
-	return fmt.Sprintf("@%s", user.Login)
+	if badge, ok := userKindBadges[user.Kind]; ok {
+		name += fmt.Sprintf(" [%s]", badge)
+	}
+	return name
 }
 
 // formatTimestamp 格式化时间戳
@@ -96,67 +221,39 @@ func (c *Converter) formatTimestamp(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")
 }
 
+// reactionEmojis 各 reaction 类型对应的 emoji
+var reactionEmojis = map[string]string{
+	"+1":       "👍",
+	"-1":       "👎",
+	"laugh":    "😄",
+	"hooray":   "🎉",
+	"confused": "😕",
+	"heart":    "❤️",
+	"rocket":   "🚀",
+	"eyes":     "👀",
+}
+
 // formatReactions 格式化 reactions
 func (c *Converter) formatReactions(reactions []github.Reaction) string {
 	if !c.enableReactions || len(reactions) == 0 {
 		return ""
 	}
 
-	var builder strings.Builder
-	// 按照 spec.md 定义的顺序：👍❤️😄🎉
-	order := map[string]int{
-		"+1":     1,
-		"heart":  2,
-		"laugh":  3,
-		"hooray": 4,
-	}
-
 	// 按类型分组统计
 	counts := make(map[string]int)
 	for _, r := range reactions {
 		counts[r.Content] += r.Count
 	}
 
-	// 按顺序输出
-	type reactionItem struct {
-		emoji string
-		count int
-		order int
-	}
-	var items []reactionItem
-	emojiLookup := map[string]string{
-		"+1":     "👍",
-		"heart":  "❤️",
-		"laugh":  "😄",
-		"hooray": "🎉",
-	}
-
-	for content, count := range counts {
-		if count > 0 {
-			if emoji, ok := emojiLookup[content]; ok {
-				items = append(items, reactionItem{
-					emoji: emoji,
-					count: count,
-					order: order[content],
-				})
-			}
-		}
-	}
-
-	// 排序
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].order < items[j].order
-	})
-
-	// 构建输出
-	for i, item := range items {
-		if i > 0 {
-			builder.WriteString(" ")
+	// 按 GitHub 的显示顺序输出
+	var items []string
+	for _, content := range github.ReactionContents {
+		if counts[content] > 0 {
+			items = append(items, fmt.Sprintf("%s %d", reactionEmojis[content], counts[content]))
 		}
-		builder.WriteString(fmt.Sprintf("%s %d", item.emoji, item.count))
 	}
 
-	return builder.String()
+	return strings.Join(items, " ")
 }
 
 // convertEmojiShortcode 转换 emoji shortcode 为 Unicode emoji
@@ -168,6 +265,178 @@ func (c *Converter) convertEmojiShortcode(body string) string {
 	return result
 }
 
+// commentKindLabels PR 时间线中各类评论在标题中的标注
+var commentKindLabels = map[github.CommentKind]string{
+	github.CommentKindReview:        "Review",
+	github.CommentKindReviewComment: "代码评论",
+}
+
+// reviewStateLabels Review 结论在标题中的显示文本
+var reviewStateLabels = map[github.ReviewState]string{
+	github.ReviewStateApproved:         "✅ APPROVED",
+	github.ReviewStateChangesRequested: "❌ CHANGES_REQUESTED",
+	github.ReviewStateCommented:        "💬 COMMENTED",
+	github.ReviewStateDismissed:        "🚫 DISMISSED",
+}
+
+// commentLabel 返回评论标题中的类型标注，对话评论返回空字符串
+func commentLabel(comment github.Comment) string {
+	if comment.IsAnswer {
+		return "✅ 已采纳答案"
+	}
+
+	label, ok := commentKindLabels[comment.Kind]
+	if !ok {
+		return ""
+	}
+	if comment.Kind == github.CommentKindReview && comment.ReviewState != "" {
+		state, ok := reviewStateLabels[comment.ReviewState]
+		if !ok {
+			state = string(comment.ReviewState)
+		}
+		label = fmt.Sprintf("%s: %s", label, state)
+	}
+	return label
+}
+
+// writeComment 输出单条评论及其子评论，嵌套的子评论使用下一级标题
+// level: 标题级别（3 表示 ###）
+func (c *Converter) writeComment(builder *strings.Builder, comment github.Comment, level int) {
+	if c.skipComment(comment) {
+		return
+	}
+
+	// 带文件位置的行级评论按代码会话展示
+	if comment.Path != "" {
+		c.writeReviewThread(builder, comment, level)
+		return
+	}
+
+	c.writeCommentEntry(builder, comment, level)
+	for _, reply := range comment.Replies {
+		c.writeComment(builder, reply, childLevel(level))
+	}
+}
+
+// writeReviewThread 输出代码会话：文件与行号标题、diff 片段，然后依次输出首条评论和回复
+// 已解决/过时的会话在标题中标注，或按配置隐藏
+func (c *Converter) writeReviewThread(builder *strings.Builder, root github.Comment, level int) {
+	if (root.Resolved && c.hideResolvedThreads) || (root.Outdated && c.hideOutdatedThreads) {
+		return
+	}
+
+	location := fmt.Sprintf("`%s`", root.Path)
+	if root.Line > 0 {
+		location += fmt.Sprintf(" 第 %d 行", root.Line)
+	}
+	if root.Resolved {
+		if root.ResolvedBy.Login != "" {
+			location += fmt.Sprintf(" [已解决 by %s]", c.formatUser(root.ResolvedBy))
+		} else {
+			location += " [已解决]"
+		}
+	}
+	if root.Outdated {
+		location += " [已过时]"
+	}
+	builder.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", level), location))
+
+	if root.DiffHunk != "" {
+		builder.WriteString(codeBlock("diff", root.DiffHunk))
+		builder.WriteString("\n")
+	}
+
+	c.writeCommentEntry(builder, root, childLevel(level))
+	for _, reply := range root.Replies {
+		if c.skipComment(reply) {
+			continue
+		}
+		c.writeCommentEntry(builder, reply, childLevel(level))
+	}
+}
+
+// writeCommentEntry 输出单条评论（标题、正文、reactions），不含子评论
+func (c *Converter) writeCommentEntry(builder *strings.Builder, comment github.Comment, level int) {
+	// 评论标题，非对话评论附加类型标注（作者关系标注紧跟用户名）
+	commentTime := c.formatTimestamp(comment.CreatedAt)
+	heading := fmt.Sprintf("%s %s - %s", strings.Repeat("#", level), c.formatAuthor(comment.User, comment.AuthorAssociation), commentTime)
+	if comment.EditedAt != nil {
+		heading += editedMarker
+	}
+	if label := commentLabel(comment); label != "" {
+		heading += fmt.Sprintf(" [%s]", label)
+	}
+	if comment.Minimized {
+		heading += fmt.Sprintf(" [%s]", minimizedLabel(comment))
+	}
+	builder.WriteString(heading)
+	builder.WriteString("\n\n")
+
+	// 评论内容
+	if comment.Deleted {
+		builder.WriteString("~~deleted~~\n\n")
+	} else if comment.Minimized && c.minimizedPolicy == MinimizedCollapse {
+		c.writeMinimizedBody(builder, comment)
+	} else if comment.Body != "" {
+		commentBody := c.convertEmojiShortcode(comment.Body)
+		builder.WriteString(commentBody)
+		builder.WriteString("\n\n")
+	}
+
+	// Reactions（Discussion 评论的 upvote 一并显示）
+	reactions := c.formatReactions(comment.Reactions)
+	if c.enableReactions && comment.UpvoteCount > 0 {
+		reactions = strings.TrimSpace(fmt.Sprintf("⬆️ %d %s", comment.UpvoteCount, reactions))
+	}
+	if reactions != "" {
+		builder.WriteString(reactions)
+		builder.WriteString("\n\n")
+	}
+
+	if !comment.Deleted {
+		c.writeEditHistory(builder, comment.EditHistory)
+	}
+}
+
+// writeBody 输出正文及其 reactions 和编辑历史
+func (c *Converter) writeBody(builder *strings.Builder, body string, reactions []github.Reaction, history github.EditHistory) {
+	if body != "" {
+		builder.WriteString(c.convertEmojiShortcode(body))
+		builder.WriteString("\n\n")
+	}
+	if formatted := c.formatReactions(reactions); formatted != "" {
+		builder.WriteString(formatted)
+		builder.WriteString("\n\n")
+	}
+	c.writeEditHistory(builder, history)
+}
+
+// reactionsFrontmatter 返回正文 reactions 的 Frontmatter 字段（未启用 reactions 或没有 reactions 时为空）
+func (c *Converter) reactionsFrontmatter(reactions []github.Reaction) []frontmatterField {
+	if formatted := c.formatReactions(reactions); formatted != "" {
+		return []frontmatterField{{key: "reactions", value: formatted}}
+	}
+	return nil
+}
+
+// childLevel 返回子评论的标题级别（Markdown 标题最多 6 级）
+func childLevel(level int) int {
+	if level >= 6 {
+		return 6
+	}
+	return level + 1
+}
+
+// codeBlock 将内容包装为带语言标注的代码块
+// 内容中包含反引号序列时使用更长的围栏，避免提前闭合
+func codeBlock(lang, content string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(content, "\n"), fence)
+}
+
 // ConvertIssue 转换 Issue 为 Markdown
 func (c *Converter) ConvertIssue(issue *github.Issue) (string, error) {
 	var builder strings.Builder
@@ -181,47 +450,38 @@ func (c *Converter) ConvertIssue(issue *github.Issue) (string, error) {
 		author,
 		createdAt,
 		issue.State,
+		concatFrontmatter(c.associationFrontmatter(issue.AuthorAssociation), c.editFrontmatter(issue.EditHistory), c.metadataFrontmatter(issue.Metadata), c.trackingFrontmatter(issue), c.reactionsFrontmatter(issue.Reactions))...,
 	))
 
 	// 2. 标题
 	builder.WriteString(fmt.Sprintf("# %s\n\n", issue.Title))
 
 	// 3. 元数据
-	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatUser(issue.User)))
+	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatAuthor(issue.User, issue.AuthorAssociation)))
 	builder.WriteString(fmt.Sprintf("**创建时间**: %s\n", createdAt))
 	statusDisplay := title(issue.State)
-	builder.WriteString(fmt.Sprintf("**状态**: %s\n\n", statusDisplay))
+	builder.WriteString(fmt.Sprintf("**状态**: %s\n", statusDisplay))
+	c.writeEditMetadata(&builder, issue.EditHistory)
+	c.writeMetadata(&builder, issue.Metadata)
+	c.writeTrackingMetadata(&builder, issue)
+	builder.WriteString("\n")
 
 	// 4. 正文
-	if issue.Body != "" {
-		body := c.convertEmojiShortcode(issue.Body)
-		builder.WriteString(body)
-		builder.WriteString("\n\n")
+	c.writeBody(&builder, issue.Body, issue.Reactions, issue.EditHistory)
+
+	// 5. 子 Issue
+	if len(issue.SubIssues) > 0 {
+		c.writeSubIssues(&builder, issue.SubIssues)
 	}
 
-	// 5. 评论
-	if len(issue.Comments) > 0 {
+	// 6. 评论（启用事件时与时间线事件按时间交错）
+	if c.enableEvents && len(issue.Events) > 0 {
+		builder.WriteString("## 时间线\n\n")
+		c.writeIssueTimeline(&builder, issue.Comments, issue.Events)
+	} else if len(issue.Comments) > 0 {
 		builder.WriteString("## 评论\n\n")
 		for _, comment := range issue.Comments {
-			// 评论标题
-			commentTime := c.formatTimestamp(comment.CreatedAt)
-			builder.WriteString(fmt.Sprintf("### %s - %s\n\n", c.formatUser(comment.User), commentTime))
-
-			// 评论内容
-			if comment.Deleted {
-				builder.WriteString("~~deleted~~\n\n")
-			} else if comment.Body != "" {
-				commentBody := c.convertEmojiShortcode(comment.Body)
-				builder.WriteString(commentBody)
-				builder.WriteString("\n\n")
-			}
-
-			// Reactions
-			reactions := c.formatReactions(comment.Reactions)
-			if reactions != "" {
-				builder.WriteString(reactions)
-				builder.WriteString("\n\n")
-			}
+			c.writeComment(&builder, comment, 3)
 		}
 	}
 
@@ -241,53 +501,72 @@ func (c *Converter) ConvertPullRequest(pr *github.PullRequest) (string, error) {
 		author,
 		createdAt,
 		pr.State,
+		concatFrontmatter(c.associationFrontmatter(pr.AuthorAssociation), c.editFrontmatter(pr.EditHistory), c.metadataFrontmatter(pr.Metadata), c.pullRequestFrontmatter(pr))...,
 	))
 
 	// 2. 标题
 	builder.WriteString(fmt.Sprintf("# %s\n\n", pr.Title))
 
 	// 3. 元数据
-	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatUser(pr.User)))
+	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatAuthor(pr.User, pr.AuthorAssociation)))
 	builder.WriteString(fmt.Sprintf("**创建时间**: %s\n", createdAt))
 	statusDisplay := title(pr.State)
-	builder.WriteString(fmt.Sprintf("**状态**: %s\n\n", statusDisplay))
+	if pr.Draft {
+		statusDisplay += "（草稿）"
+	}
+	builder.WriteString(fmt.Sprintf("**状态**: %s\n", statusDisplay))
+	c.writeEditMetadata(&builder, pr.EditHistory)
+	c.writeMetadata(&builder, pr.Metadata)
+	c.writePullRequestMetadata(&builder, pr)
+	builder.WriteString("\n")
 
 	// 4. 正文
-	if pr.Body != "" {
-		body := c.convertEmojiShortcode(pr.Body)
-		builder.WriteString(body)
-		builder.WriteString("\n\n")
+	c.writeBody(&builder, pr.Body, pr.Reactions, pr.EditHistory)
+
+	// 5. 变更文件
+	if len(pr.Files) > 0 {
+		c.writeChangedFiles(&builder, pr.Files)
+	}
+
+	// 6. 提交记录
+	if len(pr.Commits) > 0 {
+		c.writeCommits(&builder, pr.Commits)
+	}
+
+	// 7. CI 检查
+	if len(pr.Checks) > 0 {
+		c.writeChecks(&builder, pr.Checks)
 	}
 
-	// 5. 评论 (Review 评论)
+	// 8. 评论（对话评论和 Review，Review 下嵌套其行级评论，已按时间排序）
 	if len(pr.Comments) > 0 {
 		builder.WriteString("## 评论\n\n")
 		for _, comment := range pr.Comments {
-			// 评论标题
-			commentTime := c.formatTimestamp(comment.CreatedAt)
-			builder.WriteString(fmt.Sprintf("### %s - %s\n\n", c.formatUser(comment.User), commentTime))
-
-			// 评论内容
-			if comment.Deleted {
-				builder.WriteString("~~deleted~~\n\n")
-			} else if comment.Body != "" {
-				commentBody := c.convertEmojiShortcode(comment.Body)
-				builder.WriteString(commentBody)
-				builder.WriteString("\n\n")
-			}
-
-			// Reactions
-			reactions := c.formatReactions(comment.Reactions)
-			if reactions != "" {
-				builder.WriteString(reactions)
-				builder.WriteString("\n\n")
-			}
+			c.writeComment(&builder, comment, 3)
 		}
 	}
 
+	// 9. 附录：补丁
+	if c.enablePatches && len(pr.Files) > 0 {
+		c.writePatches(&builder, pr.Files)
+	}
+
 	return builder.String(), nil
 }
 
+// discussionFrontmatter 返回 Discussion 特有的 Frontmatter 字段（分类、点赞、答案）
+func (c *Converter) discussionFrontmatter(discussion *github.Discussion) []frontmatterField {
+	fields := []frontmatterField{}
+	if discussion.Category != "" {
+		fields = append(fields, frontmatterField{key: "category", value: discussion.Category})
+	}
+	fields = append(fields, frontmatterField{key: "upvotes", value: discussion.UpvoteCount})
+	if discussion.AnswerChosenBy.Login != "" {
+		fields = append(fields, frontmatterField{key: "answer_chosen_by", value: "@" + discussion.AnswerChosenBy.Login})
+	}
+	return append(fields, c.reactionsFrontmatter(discussion.Reactions)...)
+}
+
 // ConvertDiscussion 转换 Discussion 为 Markdown
 func (c *Converter) ConvertDiscussion(discussion *github.Discussion) (string, error) {
 	var builder strings.Builder
@@ -301,47 +580,42 @@ func (c *Converter) ConvertDiscussion(discussion *github.Discussion) (string, er
 		author,
 		createdAt,
 		discussion.State,
+		concatFrontmatter(c.associationFrontmatter(discussion.AuthorAssociation), c.editFrontmatter(discussion.EditHistory), c.metadataFrontmatter(discussion.Metadata), c.discussionFrontmatter(discussion))...,
 	))
 
 	// 2. 标题
 	builder.WriteString(fmt.Sprintf("# %s\n\n", discussion.Title))
 
 	// 3. 元数据
-	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatUser(discussion.User)))
+	builder.WriteString(fmt.Sprintf("**作者**: %s\n", c.formatAuthor(discussion.User, discussion.AuthorAssociation)))
 	builder.WriteString(fmt.Sprintf("**创建时间**: %s\n", createdAt))
 	statusDisplay := title(discussion.State)
-	builder.WriteString(fmt.Sprintf("**状态**: %s\n\n", statusDisplay))
+	builder.WriteString(fmt.Sprintf("**状态**: %s\n", statusDisplay))
+	c.writeEditMetadata(&builder, discussion.EditHistory)
+	c.writeMetadata(&builder, discussion.Metadata)
+	if discussion.Category != "" {
+		builder.WriteString(fmt.Sprintf("**分类**: %s\n", discussion.Category))
+	}
+	if discussion.UpvoteCount > 0 {
+		builder.WriteString(fmt.Sprintf("**点赞**: %d\n", discussion.UpvoteCount))
+	}
+	if discussion.AnswerChosenBy.Login != "" {
+		answer := fmt.Sprintf("由 %s 采纳", c.formatUser(discussion.AnswerChosenBy))
+		if discussion.AnswerChosenAt != nil {
+			answer += fmt.Sprintf("（%s）", c.formatTimestamp(*discussion.AnswerChosenAt))
+		}
+		builder.WriteString(fmt.Sprintf("**答案**: %s\n", answer))
+	}
+	builder.WriteString("\n")
 
 	// 4. 正文
-	if discussion.Body != "" {
-		body := c.convertEmojiShortcode(discussion.Body)
-		builder.WriteString(body)
-		builder.WriteString("\n\n")
-	}
+	c.writeBody(&builder, discussion.Body, discussion.Reactions, discussion.EditHistory)
 
-	// 5. 评论（包含主楼和所有回复，已按时间排序）
+	// 5. 评论（顶层评论已按时间排序，回复嵌套在各自评论下）
 	if len(discussion.Comments) > 0 {
 		builder.WriteString("## 评论\n\n")
 		for _, comment := range discussion.Comments {
-			// 评论标题
-			commentTime := c.formatTimestamp(comment.CreatedAt)
-			builder.WriteString(fmt.Sprintf("### %s - %s\n\n", c.formatUser(comment.User), commentTime))
-
-			// 评论内容
-			if comment.Deleted {
-				builder.WriteString("~~deleted~~\n\n")
-			} else if comment.Body != "" {
-				commentBody := c.convertEmojiShortcode(comment.Body)
-				builder.WriteString(commentBody)
-				builder.WriteString("\n\n")
-			}
-
-			// Reactions
-			reactions := c.formatReactions(comment.Reactions)
-			if reactions != "" {
-				builder.WriteString(reactions)
-				builder.WriteString("\n\n")
-			}
+			c.writeComment(&builder, comment, 3)
 		}
 	}
 
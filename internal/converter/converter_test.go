@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -309,6 +310,143 @@ func TestConvertPullRequest(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "PR thread tags each kind",
+			pr: &github.PullRequest{
+				Title:     "PR with Thread",
+				URL:       "https://github.com/test/repo/pull/4",
+				User:      github.User{Login: "author", HTMLURL: "https://github.com/author"},
+				CreatedAt: time.Now(),
+				State:     "open",
+				Body:      "Description",
+				Comments: []github.Comment{
+					{Kind: github.CommentKindIssue, User: github.User{Login: "alice"}, Body: "Conversation"},
+					{Kind: github.CommentKindReviewComment, User: github.User{Login: "bob"}, Body: "Inline"},
+					{Kind: github.CommentKindReview, User: github.User{Login: "carol"}, Body: "Summary"},
+				},
+			},
+			validate: func(t *testing.T, output string) {
+				if strings.Contains(output, "@alice - 0001-01-01 00:00:00 [") {
+					t.Errorf("conversation comments should not be tagged")
+				}
+				if !strings.Contains(output, "### @bob - 0001-01-01 00:00:00 [代码评论]") {
+					t.Errorf("review comments should be tagged")
+				}
+				if !strings.Contains(output, "### @carol - 0001-01-01 00:00:00 [Review]") {
+					t.Errorf("review summaries should be tagged")
+				}
+			},
+		},
+		{
+			name: "PR reviews with state and nested comments",
+			pr: &github.PullRequest{
+				Title:     "PR with Review Sections",
+				URL:       "https://github.com/test/repo/pull/5",
+				User:      github.User{Login: "author", HTMLURL: "https://github.com/author"},
+				CreatedAt: time.Now(),
+				State:     "open",
+				Body:      "Description",
+				Comments: []github.Comment{
+					{
+						Kind:        github.CommentKindReview,
+						User:        github.User{Login: "reviewer"},
+						CreatedAt:   time.Date(2025, 1, 5, 9, 0, 0, 0, time.Local),
+						Body:        "Please fix the nil check",
+						ReviewState: github.ReviewStateChangesRequested,
+						Replies: []github.Comment{
+							{
+								Kind:      github.CommentKindReviewComment,
+								User:      github.User{Login: "reviewer"},
+								CreatedAt: time.Date(2025, 1, 5, 8, 59, 0, 0, time.Local),
+								Body:      "nil deref here",
+							},
+						},
+					},
+					{
+						Kind:        github.CommentKindReview,
+						User:        github.User{Login: "lead"},
+						CreatedAt:   time.Date(2025, 1, 6, 9, 0, 0, 0, time.Local),
+						ReviewState: github.ReviewStateApproved,
+					},
+				},
+			},
+			validate: func(t *testing.T, output string) {
+				if !strings.Contains(output, "### @reviewer - 2025-01-05 09:00:00 [Review: ❌ CHANGES_REQUESTED]") {
+					t.Errorf("output should contain review heading with state")
+				}
+				if !strings.Contains(output, "#### @reviewer - 2025-01-05 08:59:00 [代码评论]") {
+					t.Errorf("inline comments should be nested under the review")
+				}
+				reviewIdx := strings.Index(output, "Please fix the nil check")
+				inlineIdx := strings.Index(output, "nil deref here")
+				if reviewIdx == -1 || inlineIdx <= reviewIdx {
+					t.Errorf("inline comments should follow the review summary")
+				}
+				if !strings.Contains(output, "### @lead - 2025-01-06 09:00:00 [Review: ✅ APPROVED]") {
+					t.Errorf("approvals without body should still be rendered")
+				}
+			},
+		},
+		{
+			name: "PR review threads with file, line and diff hunk",
+			pr: &github.PullRequest{
+				Title:     "PR with Threads",
+				URL:       "https://github.com/test/repo/pull/6",
+				User:      github.User{Login: "author", HTMLURL: "https://github.com/author"},
+				CreatedAt: time.Now(),
+				State:     "open",
+				Comments: []github.Comment{
+					{
+						Kind:        github.CommentKindReview,
+						User:        github.User{Login: "reviewer"},
+						CreatedAt:   time.Date(2025, 1, 5, 9, 0, 0, 0, time.Local),
+						ReviewState: github.ReviewStateCommented,
+						Replies: []github.Comment{
+							{
+								Kind:      github.CommentKindReviewComment,
+								User:      github.User{Login: "reviewer"},
+								CreatedAt: time.Date(2025, 1, 5, 9, 0, 0, 0, time.Local),
+								Body:      "Why not return early?",
+								Path:      "internal/github/client.go",
+								Line:      42,
+								DiffHunk:  "@@ -40,3 +40,4 @@\n func f() {\n+\tx := 1",
+								Replies: []github.Comment{
+									{
+										Kind:        github.CommentKindReviewComment,
+										User:        github.User{Login: "author"},
+										CreatedAt:   time.Date(2025, 1, 5, 10, 0, 0, 0, time.Local),
+										Body:        "Good point, fixed",
+										Path:        "internal/github/client.go",
+										Line:        42,
+										InReplyToID: 1,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			validate: func(t *testing.T, output string) {
+				if !strings.Contains(output, "#### `internal/github/client.go` 第 42 行") {
+					t.Errorf("thread heading should contain file and line")
+				}
+				if !strings.Contains(output, "```diff\n@@ -40,3 +40,4 @@\n func f() {\n+\tx := 1\n```") {
+					t.Errorf("thread should show the diff hunk")
+				}
+				if !strings.Contains(output, "##### @reviewer - 2025-01-05 09:00:00 [代码评论]") {
+					t.Errorf("thread root comment should be nested under the thread heading")
+				}
+				if !strings.Contains(output, "##### @author - 2025-01-05 10:00:00 [代码评论]") {
+					t.Errorf("replies should be at the same level as the root comment")
+				}
+				hunkIdx := strings.Index(output, "```diff")
+				rootIdx := strings.Index(output, "Why not return early?")
+				replyIdx := strings.Index(output, "Good point, fixed")
+				if hunkIdx >= rootIdx || rootIdx >= replyIdx {
+					t.Errorf("thread should render hunk, root comment, then replies")
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -327,6 +465,279 @@ func TestConvertPullRequest(t *testing.T) {
 	}
 }
 
+// TestConvertPullRequest_ThreadStates 测试已解决/过时代码会话的标注与隐藏
+func TestConvertPullRequest_ThreadStates(t *testing.T) {
+	thread := func(body string, resolved, outdated bool) github.Comment {
+		return github.Comment{
+			Kind:       github.CommentKindReviewComment,
+			User:       github.User{Login: "reviewer"},
+			Body:       body,
+			Path:       "main.go",
+			Line:       7,
+			Resolved:   resolved,
+			Outdated:   outdated,
+			ResolvedBy: github.User{Login: "maintainer"},
+		}
+	}
+	pr := &github.PullRequest{
+		Title: "PR with Thread States",
+		State: "open",
+		Comments: []github.Comment{
+			{
+				Kind:        github.CommentKindReview,
+				User:        github.User{Login: "reviewer"},
+				ReviewState: github.ReviewStateCommented,
+				Replies: []github.Comment{
+					thread("open thread", false, false),
+					thread("resolved thread", true, false),
+					thread("outdated thread", false, true),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		options     []Option
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{
+			name: "mark by default",
+			wantPresent: []string{
+				"open thread",
+				"`main.go` 第 7 行 [已解决 by @maintainer]",
+				"`main.go` 第 7 行 [已过时]",
+			},
+		},
+		{
+			name:        "hide resolved",
+			options:     []Option{WithHideResolvedThreads(true)},
+			wantPresent: []string{"open thread", "outdated thread"},
+			wantAbsent:  []string{"resolved thread", "[已解决"},
+		},
+		{
+			name:        "hide resolved and outdated",
+			options:     []Option{WithHideResolvedThreads(true), WithHideOutdatedThreads(true)},
+			wantPresent: []string{"open thread"},
+			wantAbsent:  []string{"resolved thread", "outdated thread"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter(tt.options...).ConvertPullRequest(pr)
+			if err != nil {
+				t.Fatalf("ConvertPullRequest() error = %v", err)
+			}
+			for _, want := range tt.wantPresent {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.wantAbsent {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("output should not contain %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+// TestConvertPullRequest_ChangedFiles 测试变更文件表格和补丁附录
+func TestConvertPullRequest_ChangedFiles(t *testing.T) {
+	pr := &github.PullRequest{
+		Title: "PR with Files",
+		State: "open",
+		Files: []github.ChangedFile{
+			{Filename: "main.go", Status: "modified", Additions: 3, Deletions: 1,
+				Patch: "@@ -1,2 +1,4 @@\n-old\n+new\n+line two\n+line three"},
+			{Filename: "logo.png", Status: "added", Additions: 0, Deletions: 0},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		options     []Option
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{
+			name: "table without patches by default",
+			wantPresent: []string{
+				"## 变更文件",
+				"共 2 个文件，+3 -1",
+				"| `main.go` | modified | +3 -1 | 🟩🟩🟩🟥⬜ |",
+				"| `logo.png` | added | +0 -0 | ⬜⬜⬜⬜⬜ |",
+			},
+			wantAbsent: []string{"## 附录：补丁", "```diff"},
+		},
+		{
+			name:    "patches appendix",
+			options: []Option{WithPatches(true)},
+			wantPresent: []string{
+				"## 附录：补丁",
+				"### `main.go`\n\n```diff\n@@ -1,2 +1,4 @@\n-old\n+new\n+line two\n+line three\n```",
+				"### `logo.png`\n\n*（无补丁：二进制文件或变更过大）*",
+			},
+			wantAbsent: []string{"补丁已截断"},
+		},
+		{
+			name:    "patches truncated at line boundary",
+			options: []Option{WithPatches(true), WithPatchSizeLimit(30)},
+			wantPresent: []string{
+				"```diff\n@@ -1,2 +1,4 @@\n-old\n+new\n```",
+				"*（补丁已截断，完整大小 47 字节）*",
+			},
+			wantAbsent: []string{"+line two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter(tt.options...).ConvertPullRequest(pr)
+			if err != nil {
+				t.Fatalf("ConvertPullRequest() error = %v", err)
+			}
+			for _, want := range tt.wantPresent {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.wantAbsent {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("output should not contain %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+// TestConvertPullRequest_MergeMetadata 测试 PR 分支、合并信息和提交记录
+func TestConvertPullRequest_MergeMetadata(t *testing.T) {
+	mergedAt := time.Date(2025, 1, 6, 12, 0, 0, 0, time.Local)
+	pr := &github.PullRequest{
+		Title:          "Merged PR",
+		URL:            "https://github.com/test/repo/pull/7",
+		User:           github.User{Login: "dev"},
+		State:          "merged",
+		BaseRef:        "main",
+		HeadRef:        "feature/login",
+		HeadSHA:        "0123456789abcdef0123456789abcdef01234567",
+		MergeCommitSHA: "fedcba9876543210fedcba9876543210fedcba98",
+		MergedBy:       github.User{Login: "maintainer"},
+		MergedAt:       &mergedAt,
+		Commits: []github.Commit{
+			{
+				SHA:        "aaaaaaa1111111",
+				URL:        "https://github.com/test/repo/commit/aaaaaaa1111111",
+				Message:    "Add login form\n\nLonger description",
+				AuthorName: "Dev Eloper",
+				Author:     github.User{Login: "dev"},
+				Date:       time.Date(2025, 1, 5, 10, 0, 0, 0, time.Local),
+			},
+			{
+				SHA:        "bbbbbbb2222222",
+				Message:    "Fix typo",
+				AuthorName: "Unlinked Author",
+				Date:       time.Date(2025, 1, 5, 11, 0, 0, 0, time.Local),
+			},
+		},
+	}
+
+	output, err := NewConverter().ConvertPullRequest(pr)
+	if err != nil {
+		t.Fatalf("ConvertPullRequest() error = %v", err)
+	}
+
+	wantPresent := []string{
+		"base: \"main\"\n",
+		"head: \"feature/login\"\n",
+		"head_sha: \"0123456789abcdef0123456789abcdef01234567\"\n",
+		"draft: false\n",
+		"merge_commit_sha: \"fedcba9876543210fedcba9876543210fedcba98\"\n",
+		"merged_by: \"@maintainer\"\n",
+		"merged_at: \"2025-01-06 12:00:00\"\n",
+		"**分支**: `feature/login` → `main`",
+		"**合并**: @maintainer 于 2025-01-06 12:00:00（`fedcba9`）",
+		"## 提交记录",
+		"- [`aaaaaaa`](https://github.com/test/repo/commit/aaaaaaa1111111) Add login form — @dev (2025-01-05 10:00:00)",
+		"- `bbbbbbb` Fix typo — Unlinked Author (2025-01-05 11:00:00)",
+	}
+	for _, want := range wantPresent {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+
+	if strings.Contains(output, "Longer description") {
+		t.Errorf("commit list should only show the message subject")
+	}
+
+	// Frontmatter 附加字段应位于分隔符之间
+	frontmatterEnd := strings.Index(output[3:], "---") + 3
+	if strings.Index(output, "merged_at:") > frontmatterEnd {
+		t.Errorf("merge metadata should be inside the frontmatter")
+	}
+}
+
+// TestConvertPullRequest_Checks 测试 CI 检查表格
+func TestConvertPullRequest_Checks(t *testing.T) {
+	started := time.Date(2025, 1, 5, 10, 0, 0, 0, time.Local)
+	completed := started.Add(2*time.Minute + 5*time.Second)
+	pr := &github.PullRequest{
+		Title: "PR with Checks",
+		State: "open",
+		Checks: []github.Check{
+			{Name: "build", Kind: github.CheckKindCheckRun, Status: "completed", Conclusion: "success",
+				StartedAt: &started, CompletedAt: &completed, DetailsURL: "https://ci.example.com/build/1"},
+			{Name: "lint", Kind: github.CheckKindCheckRun, Status: "in_progress", StartedAt: &started},
+			{Name: "ci/jenkins", Kind: github.CheckKindStatus, Conclusion: "failure"},
+		},
+	}
+
+	output, err := NewConverter().ConvertPullRequest(pr)
+	if err != nil {
+		t.Fatalf("ConvertPullRequest() error = %v", err)
+	}
+
+	wantPresent := []string{
+		"## CI 检查",
+		"| build | ✅ success | 2m5s | [查看](https://ci.example.com/build/1) |",
+		"| lint | in_progress | - | - |",
+		"| ci/jenkins | ❌ failure | - | - |",
+	}
+	for _, want := range wantPresent {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+}
+
+// TestDiffstatBar 测试 diffstat 条形图
+func TestDiffstatBar(t *testing.T) {
+	tests := []struct {
+		additions int
+		deletions int
+		want      string
+	}{
+		{0, 0, "⬜⬜⬜⬜⬜"},
+		{10, 0, "🟩🟩🟩🟩🟩"},
+		{0, 10, "🟥🟥🟥🟥🟥"},
+		{1, 1, "🟩🟥⬜⬜⬜"},
+		{1, 99, "🟩🟥🟥🟥🟥"},
+		{50, 50, "🟩🟩🟥🟥🟥"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("+%d -%d", tt.additions, tt.deletions), func(t *testing.T) {
+			if got := diffstatBar(tt.additions, tt.deletions); got != tt.want {
+				t.Errorf("diffstatBar(%d, %d) = %q, want %q", tt.additions, tt.deletions, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestConvertDiscussion 测试 Discussion 转换功能
 func TestConvertDiscussion(t *testing.T) {
 	tests := []struct {
@@ -395,6 +806,64 @@ func TestConvertDiscussion(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Q&A discussion with nested replies and accepted answer",
+			discussion: &github.Discussion{
+				Title:          "How do I paginate?",
+				URL:            "https://github.com/test/repo/discussions/3",
+				User:           github.User{Login: "asker"},
+				CreatedAt:      time.Date(2025, 1, 4, 9, 0, 0, 0, time.Local),
+				State:          "open",
+				Body:           "Question body",
+				Category:       "Q&A",
+				UpvoteCount:    12,
+				AnswerChosenBy: github.User{Login: "asker"},
+				Comments: []github.Comment{
+					{
+						User:      github.User{Login: "helper"},
+						CreatedAt: time.Date(2025, 1, 4, 10, 0, 0, 0, time.Local),
+						Body:      "Use the Link header",
+						IsAnswer:  true,
+						Replies: []github.Comment{
+							{
+								User:      github.User{Login: "asker"},
+								CreatedAt: time.Date(2025, 1, 4, 11, 0, 0, 0, time.Local),
+								Body:      "Thanks, that worked",
+							},
+						},
+					},
+					{
+						User:      github.User{Login: "other"},
+						CreatedAt: time.Date(2025, 1, 4, 12, 0, 0, 0, time.Local),
+						Body:      "Another idea",
+					},
+				},
+			},
+			validate: func(t *testing.T, output string) {
+				wantPresent := []string{
+					"category: \"Q&A\"\n",
+					"upvotes: 12\n",
+					"answer_chosen_by: \"@asker\"\n",
+					"**分类**: Q&A",
+					"**点赞**: 12",
+					"**答案**: 由 @asker 采纳",
+					"### @helper - 2025-01-04 10:00:00 [✅ 已采纳答案]",
+					"#### @asker - 2025-01-04 11:00:00",
+					"### @other - 2025-01-04 12:00:00",
+				}
+				for _, want := range wantPresent {
+					if !strings.Contains(output, want) {
+						t.Errorf("output should contain %q", want)
+					}
+				}
+				// 回复紧跟在其父评论之后，位于下一条顶层评论之前
+				replyIdx := strings.Index(output, "Thanks, that worked")
+				nextIdx := strings.Index(output, "Another idea")
+				if replyIdx == -1 || replyIdx >= nextIdx {
+					t.Errorf("replies should be nested under their parent comment")
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -457,3 +926,548 @@ func TestConvertEmojiShortcode(t *testing.T) {
 		})
 	}
 }
+
+// TestFormatReactions_AllTypes 测试全部 reaction 类型按 GitHub 顺序输出
+func TestFormatReactions_AllTypes(t *testing.T) {
+	c := NewConverter(WithReactions(true))
+
+	got := c.formatReactions([]github.Reaction{
+		{Content: "eyes", Count: 8},
+		{Content: "rocket", Count: 7},
+		{Content: "heart", Count: 6},
+		{Content: "confused", Count: 5},
+		{Content: "hooray", Count: 4},
+		{Content: "laugh", Count: 3},
+		{Content: "-1", Count: 2},
+		{Content: "+1", Count: 1},
+		{Content: "unknown", Count: 9},
+	})
+
+	want := "👍 1 👎 2 😄 3 🎉 4 😕 5 ❤️ 6 🚀 7 👀 8"
+	if got != want {
+		t.Errorf("formatReactions() = %q, want %q", got, want)
+	}
+}
+
+// TestConvert_BodyReactions 测试正文 reactions 输出在正文下方和 Frontmatter 中
+func TestConvert_BodyReactions(t *testing.T) {
+	reactions := []github.Reaction{{Content: "heart", Count: 2}, {Content: "+1", Count: 5}}
+
+	issue := createTestIssue("Test", "Issue body", nil)
+	issue.Reactions = reactions
+
+	output, err := NewConverter(WithReactions(true)).ConvertIssue(issue)
+	if err != nil {
+		t.Fatalf("ConvertIssue failed: %v", err)
+	}
+
+	if !strings.Contains(output, `reactions: "👍 5 ❤️ 2"`) {
+		t.Errorf("frontmatter should contain body reactions, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Issue body\n\n👍 5 ❤️ 2\n\n") {
+		t.Errorf("reactions should follow the body, got:\n%s", output)
+	}
+
+	pr := &github.PullRequest{Title: "PR", Body: "PR body", Reactions: reactions}
+	output, err = NewConverter(WithReactions(true)).ConvertPullRequest(pr)
+	if err != nil {
+		t.Fatalf("ConvertPullRequest failed: %v", err)
+	}
+	if !strings.Contains(output, `reactions: "👍 5 ❤️ 2"`) || !strings.Contains(output, "PR body\n\n👍 5 ❤️ 2\n\n") {
+		t.Errorf("PR output should contain body reactions, got:\n%s", output)
+	}
+
+	discussion := &github.Discussion{Title: "Discussion", Body: "Discussion body", Reactions: reactions}
+	output, err = NewConverter(WithReactions(true)).ConvertDiscussion(discussion)
+	if err != nil {
+		t.Fatalf("ConvertDiscussion failed: %v", err)
+	}
+	if !strings.Contains(output, `reactions: "👍 5 ❤️ 2"`) || !strings.Contains(output, "Discussion body\n\n👍 5 ❤️ 2\n\n") {
+		t.Errorf("discussion output should contain body reactions, got:\n%s", output)
+	}
+
+	output, err = NewConverter().ConvertIssue(issue)
+	if err != nil {
+		t.Fatalf("ConvertIssue failed: %v", err)
+	}
+	if strings.Contains(output, "reactions:") || strings.Contains(output, "👍 5") {
+		t.Errorf("reactions should be omitted when disabled, got:\n%s", output)
+	}
+}
+
+// TestConvertIssue_TimelineEvents 测试时间线事件与评论按时间交错输出
+func TestConvertIssue_TimelineEvents(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	issue := createTestIssue("Test", "Body", []github.Comment{
+		createTestComment("bob", "First comment", base.Add(2*time.Hour), nil),
+		createTestComment("alice", "Fixed in main", base.Add(4*time.Hour), nil),
+	})
+	issue.Events = []github.TimelineEvent{
+		{Type: github.EventLabeled, Actor: github.User{Login: "alice"}, CreatedAt: base, Label: "bug"},
+		{Type: github.EventAssigned, Actor: github.User{Login: "alice"}, CreatedAt: base.Add(time.Hour), Assignee: github.User{Login: "bob"}},
+		{Type: github.EventCrossReferenced, Actor: github.User{Login: "carol"}, CreatedAt: base.Add(3 * time.Hour),
+			SourceNumber: 9, SourceTitle: "Other", SourceURL: "https://github.com/test/repo/pull/9"},
+		{Type: github.EventClosed, Actor: github.User{Login: "alice"}, CreatedAt: base.Add(4 * time.Hour), StateReason: "completed"},
+		{Type: github.TimelineEventType("subscribed"), Actor: github.User{Login: "dave"}, CreatedAt: base.Add(5 * time.Hour)},
+	}
+
+	output, err := NewConverter(WithTimelineEvents(true)).ConvertIssue(issue)
+	if err != nil {
+		t.Fatalf("ConvertIssue failed: %v", err)
+	}
+
+	wantOrder := []string{
+		"## 时间线",
+		"- @alice 添加了标签 `bug` — 2024-05-01 10:00:00\n- @alice 指派给 @bob — 2024-05-01 11:00:00\n\n",
+		"### @bob - 2024-05-01 12:00:00",
+		"- @carol 在 [#9 Other](https://github.com/test/repo/pull/9) 中引用了此 Issue — 2024-05-01 13:00:00",
+		"- @alice 关闭了此 Issue（已完成） — 2024-05-01 14:00:00",
+		"### @alice - 2024-05-01 14:00:00",
+	}
+	last := -1
+	for _, want := range wantOrder {
+		idx := strings.Index(output, want)
+		if idx <= last {
+			t.Fatalf("expected %q after previous entries, got:\n%s", want, output)
+		}
+		last = idx
+	}
+	if strings.Contains(output, "dave") {
+		t.Errorf("unsupported events should be skipped, got:\n%s", output)
+	}
+
+	output, err = NewConverter().ConvertIssue(issue)
+	if err != nil {
+		t.Fatalf("ConvertIssue failed: %v", err)
+	}
+	if strings.Contains(output, "添加了标签") || !strings.Contains(output, "## 评论") {
+		t.Errorf("events should be omitted when disabled, got:\n%s", output)
+	}
+}
+
+func TestConvert_Metadata(t *testing.T) {
+	closedAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		meta    github.Metadata
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "full metadata",
+			meta: github.Metadata{
+				Labels:       []string{"bug", "help wanted"},
+				Assignees:    []github.User{{Login: "alice"}, {Login: "bob"}},
+				Milestone:    "v1.0",
+				ClosedAt:     &closedAt,
+				ClosedBy:     github.User{Login: "alice"},
+				StateReason:  "completed",
+				Locked:       true,
+				CommentCount: 3,
+			},
+			want: []string{
+				"labels:\n  - \"bug\"\n  - \"help wanted\"\n",
+				"assignees:\n  - \"@alice\"\n  - \"@bob\"\n",
+				"milestone: \"v1.0\"\n",
+				"closed_at: \"2024-05-02 09:00:00\"\n",
+				"closed_by: \"@alice\"\n",
+				"state_reason: \"completed\"\n",
+				"locked: true\n",
+				"comments: 3\n",
+				"**标签**: `bug`, `help wanted`\n",
+				"**指派**: @alice, @bob\n",
+				"**里程碑**: v1.0\n",
+				"**关闭时间**: 2024-05-02 09:00:00，由 @alice 关闭（已完成）\n",
+				"**已锁定**: 是\n",
+			},
+		},
+		{
+			name: "empty metadata",
+			meta: github.Metadata{},
+			want: []string{"comments: 0\n"},
+			notWant: []string{
+				"labels:", "assignees:", "milestone:", "closed_at:", "locked:",
+				"**标签**", "**指派**", "**里程碑**", "**关闭时间**", "**已锁定**",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := createTestIssue("Test", "Body", nil)
+			issue.Metadata = tt.meta
+			pr := &github.PullRequest{Title: "PR", URL: "https://github.com/test/repo/pull/2", State: "open", Metadata: tt.meta}
+			discussion := &github.Discussion{Title: "D", URL: "https://github.com/test/repo/discussions/3", Metadata: tt.meta}
+
+			conv := NewConverter()
+			outputs := map[string]func() (string, error){
+				"issue":      func() (string, error) { return conv.ConvertIssue(issue) },
+				"pr":         func() (string, error) { return conv.ConvertPullRequest(pr) },
+				"discussion": func() (string, error) { return conv.ConvertDiscussion(discussion) },
+			}
+			for kind, convert := range outputs {
+				output, err := convert()
+				if err != nil {
+					t.Fatalf("%s: convert failed: %v", kind, err)
+				}
+				for _, want := range tt.want {
+					if !strings.Contains(output, want) {
+						t.Errorf("%s: output should contain %q, got:\n%s", kind, want, output)
+					}
+				}
+				for _, notWant := range tt.notWant {
+					if strings.Contains(output, notWant) {
+						t.Errorf("%s: output should not contain %q, got:\n%s", kind, notWant, output)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{name: "unchanged", before: "a\nb", after: "a\nb", want: "  a\n  b\n"},
+		{name: "line changed", before: "a\nb\nc", after: "a\nx\nc", want: "  a\n- b\n+ x\n  c\n"},
+		{name: "line appended", before: "a", after: "a\nb\n", want: "  a\n+ b\n"},
+		{name: "from empty", before: "", after: "a", want: "+ a\n"},
+		{name: "crlf normalized", before: "a\r\nb", after: "a\nb", want: "  a\n  b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.before, tt.after); got != tt.want {
+				t.Errorf("lineDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert_EditHistory(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	editedAt := base.Add(time.Hour)
+	history := github.EditHistory{
+		EditedAt: &editedAt,
+		Editor:   github.User{Login: "alice"},
+		Revisions: []github.Revision{
+			{EditedAt: base, Editor: github.User{Login: "alice"}, Body: "first\nsecond"},
+			{EditedAt: editedAt, Editor: github.User{Login: "alice"}, Body: "first\nsecond, fixed"},
+		},
+	}
+
+	edited := createTestComment("bob", "Edited comment", base, nil)
+	edited.EditHistory = history
+	issue := createTestIssue("Test", "first\nsecond, fixed", []github.Comment{
+		edited,
+		createTestComment("carol", "Untouched", base.Add(2*time.Hour), nil),
+	})
+	issue.EditHistory = history
+
+	tests := []struct {
+		name        string
+		editHistory bool
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:        "markers only",
+			editHistory: false,
+			want: []string{
+				"edited_at: \"2024-05-01 11:00:00\"\nedited_by: \"@alice\"\n",
+				"**最后编辑**: 2024-05-01 11:00:00，由 @alice 编辑\n",
+				"### @bob - 2024-05-01 10:00:00（已编辑）\n",
+				"### @carol - 2024-05-01 12:00:00\n",
+			},
+			notWant: []string{"<details>", "@carol - 2024-05-01 12:00:00（已编辑）"},
+		},
+		{
+			name:        "with history",
+			editHistory: true,
+			want: []string{
+				"<summary>编辑历史（1 次编辑）</summary>\n\n**原始内容** — 2024-05-01 10:00:00 @alice\n\n```markdown\nfirst\nsecond\n```\n",
+				"**编辑** — 2024-05-01 11:00:00 @alice\n\n```diff\n  first\n- second\n+ second, fixed\n```\n\n</details>\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter(WithEditHistory(tt.editHistory)).ConvertIssue(issue)
+			if err != nil {
+				t.Fatalf("ConvertIssue failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, output)
+				}
+			}
+			if tt.editHistory && strings.Count(output, "<details>") != 2 {
+				t.Errorf("expected history for body and edited comment, got:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestConvert_EditHistoryTooLargeToDiff(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	editedAt := base.Add(time.Hour)
+	original := strings.Repeat("line\n", 1100)
+	issue := createTestIssue("Test", "changed\n"+original, nil)
+	issue.EditHistory = github.EditHistory{
+		EditedAt: &editedAt,
+		Revisions: []github.Revision{
+			{EditedAt: base, Body: original},
+			{EditedAt: editedAt, Body: "changed\n" + original},
+		},
+	}
+
+	output, err := NewConverter(WithEditHistory(true)).ConvertIssue(issue)
+	if err != nil {
+		t.Fatalf("ConvertIssue failed: %v", err)
+	}
+	if want := "**编辑** — 2024-05-01 11:00:00（内容过长，显示完整内容）\n\n```markdown\nchanged\nline\n"; !strings.Contains(output, want) {
+		t.Errorf("output should contain %q, got:\n%s", want, output)
+	}
+	if strings.Contains(output, "```diff") {
+		t.Errorf("large revisions should not be diffed, got:\n%s", output)
+	}
+}
+
+func TestConvert_MinimizedComments(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	spam := createTestComment("spammer", "buy now", base, nil)
+	spam.Minimized = true
+	spam.MinimizedReason = "spam"
+	issue := createTestIssue("Test", "Body", []github.Comment{
+		spam,
+		createTestComment("bob", "useful", base.Add(time.Hour), nil),
+	})
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "collapse by default",
+			want: []string{
+				"### @spammer - 2024-05-01 10:00:00 [已隐藏: 垃圾信息]\n\n<details>\n<summary>此评论已被隐藏（垃圾信息）</summary>\n\nbuy now\n\n</details>\n",
+				"### @bob - 2024-05-01 11:00:00\n\nuseful",
+			},
+		},
+		{
+			name:    "show",
+			options: []Option{WithMinimizedPolicy(MinimizedShow)},
+			want:    []string{"### @spammer - 2024-05-01 10:00:00 [已隐藏: 垃圾信息]\n\nbuy now\n"},
+			notWant: []string{"<details>"},
+		},
+		{
+			name:    "omit",
+			options: []Option{WithMinimizedPolicy(MinimizedOmit)},
+			want:    []string{"## 评论\n\n### @bob"},
+			notWant: []string{"spammer", "buy now"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter(tt.options...).ConvertIssue(issue)
+			if err != nil {
+				t.Fatalf("ConvertIssue failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, output)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatUser_Kinds(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      github.User
+		userLinks bool
+		want      string
+	}{
+		{name: "user", user: github.User{Login: "alice", Kind: github.UserKindUser}, want: "@alice"},
+		{name: "kind unset", user: github.User{Login: "alice"}, want: "@alice"},
+		{name: "bot", user: github.User{Login: "dependabot", Kind: github.UserKindBot}, want: "@dependabot [bot]"},
+		{name: "ghost", user: github.User{Login: "ghost", Kind: github.UserKindGhost}, want: "@ghost [已删除]"},
+		{name: "mannequin", user: github.User{Login: "old-user", Kind: github.UserKindMannequin}, want: "@old-user [占位账号]"},
+		{name: "missing login", user: github.User{}, want: "未知用户"},
+		{
+			name:      "bot with link",
+			user:      github.User{Login: "dependabot", HTMLURL: "https://github.com/apps/dependabot", Kind: github.UserKindBot},
+			userLinks: true,
+			want:      "[@dependabot](https://github.com/apps/dependabot) [bot]",
+		},
+		{name: "link without url", user: github.User{Login: "alice"}, userLinks: true, want: "@alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewConverter(WithUserLinks(tt.userLinks)).formatUser(tt.user); got != tt.want {
+				t.Errorf("formatUser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert_AuthorAssociation(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	question := createTestComment("carol", "How do I configure this?", base, nil)
+	question.AuthorAssociation = github.AssociationNone
+	answer := createTestComment("alice", "Use the config file.", base.Add(time.Hour), nil)
+	answer.AuthorAssociation = github.AssociationOwner
+	question.Replies = []github.Comment{answer}
+	noise := createTestComment("dave", "+1", base.Add(2*time.Hour), nil)
+	noise.AuthorAssociation = github.AssociationContributor
+	member := createTestComment("erin", "Fixed in v2.", base.Add(3*time.Hour), nil)
+	member.AuthorAssociation = github.AssociationMember
+
+	discussion := &github.Discussion{
+		Title:             "Question",
+		URL:               "https://github.com/test/repo/discussions/1",
+		User:              github.User{Login: "carol"},
+		AuthorAssociation: github.AssociationFirstTimeContributor,
+		State:             "open",
+		Body:              "Body",
+		Comments:          []github.Comment{question, noise, member},
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "no badges by default",
+			want: []string{
+				"author_association: \"FIRST_TIME_CONTRIBUTOR\"\n",
+				"**作者**: @carol\n",
+				"#### @alice - 2024-05-01 11:00:00\n",
+				"### @dave - 2024-05-01 12:00:00\n",
+			},
+			notWant: []string{"[所有者]", "[首次贡献者]"},
+		},
+		{
+			name:    "badges",
+			options: []Option{WithAuthorBadges(true)},
+			want: []string{
+				"**作者**: @carol [首次贡献者]\n",
+				"### @carol - 2024-05-01 10:00:00\n",
+				"#### @alice [所有者] - 2024-05-01 11:00:00\n",
+				"### @dave [贡献者] - 2024-05-01 12:00:00\n",
+				"### @erin [成员] - 2024-05-01 13:00:00\n",
+			},
+		},
+		{
+			name:    "maintainers only keeps context",
+			options: []Option{WithMaintainersOnly(true)},
+			want: []string{
+				"### @carol - 2024-05-01 10:00:00\n",
+				"#### @alice - 2024-05-01 11:00:00\n",
+				"### @erin - 2024-05-01 13:00:00\n",
+			},
+			notWant: []string{"@dave"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter(tt.options...).ConvertDiscussion(discussion)
+			if err != nil {
+				t.Fatalf("ConvertDiscussion failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, output)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertIssue_SubIssues(t *testing.T) {
+	epic := createTestIssue("Epic", "- [x] design\n- [ ] rollout", []github.Comment{
+		createTestComment("bob", "Progress update", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), nil),
+	})
+	epic.Tasks = []github.Task{{Text: "design", Done: true}, {Text: "rollout"}}
+	epic.Parent = &github.IssueRef{Number: 1, Title: "Roadmap", State: "open", URL: "https://github.com/test/repo/issues/1"}
+	epic.SubIssues = []github.IssueRef{
+		{Number: 6, Title: "Backend", State: "closed", StateReason: "completed", URL: "https://github.com/test/repo/issues/6"},
+		{Number: 7, Title: "Frontend", State: "open", URL: "https://github.com/test/repo/issues/7"},
+		{Number: 8, Title: "Legacy", State: "closed", StateReason: "not_planned"},
+	}
+
+	plain := createTestIssue("Plain", "No tasks here", nil)
+
+	tests := []struct {
+		name    string
+		issue   *github.Issue
+		want    []string
+		notWant []string
+	}{
+		{
+			name:  "tracking issue",
+			issue: epic,
+			want: []string{
+				"parent_issue: \"https://github.com/test/repo/issues/1\"\ncompletion: 60\n",
+				"**父 Issue**: [#1 Roadmap](https://github.com/test/repo/issues/1)\n**进度**: 3/5（60%）\n",
+				"## 子 Issue\n\n" +
+					"- [x] [#6 Backend](https://github.com/test/repo/issues/6) — Closed（已完成）\n" +
+					"- [ ] [#7 Frontend](https://github.com/test/repo/issues/7) — Open\n" +
+					"- [x] #8 Legacy — Closed（不计划处理）\n\n## 评论",
+			},
+		},
+		{
+			name:    "plain issue",
+			issue:   plain,
+			notWant: []string{"parent_issue", "completion", "**父 Issue**", "**进度**", "## 子 Issue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewConverter().ConvertIssue(tt.issue)
+			if err != nil {
+				t.Fatalf("ConvertIssue failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, output)
+				}
+			}
+		})
+	}
+}
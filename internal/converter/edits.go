@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// editedMarker 评论标题中的已编辑标记
+const editedMarker = "（已编辑）"
+
+// maxDiffCells lineDiff 动态规划表的最大单元格数（两个版本行数之积），超过时不计算差异
+const maxDiffCells = 1 << 20
+
+// editFrontmatter 返回正文最后编辑信息的 Frontmatter 字段（未编辑时为空）
+func (c *Converter) editFrontmatter(history github.EditHistory) []frontmatterField {
+	if history.EditedAt == nil {
+		return nil
+	}
+	fields := []frontmatterField{{key: "edited_at", value: c.formatTimestamp(*history.EditedAt)}}
+	if history.Editor.Login != "" {
+		fields = append(fields, frontmatterField{key: "edited_by", value: "@" + history.Editor.Login})
+	}
+	return fields
+}
+
+// writeEditMetadata 在元数据区输出正文的最后编辑信息
+func (c *Converter) writeEditMetadata(builder *strings.Builder, history github.EditHistory) {
+	if history.EditedAt == nil {
+		return
+	}
+	edited := c.formatTimestamp(*history.EditedAt)
+	if history.Editor.Login != "" {
+		edited += fmt.Sprintf("，由 %s 编辑", c.formatUser(history.Editor))
+	}
+	builder.WriteString(fmt.Sprintf("**最后编辑**: %s\n", edited))
+}
+
+// writeEditHistory 将历史版本输出为可折叠区块：首个版本显示原始内容，之后每次编辑显示与上一版本的差异
+// 版本过长无法比较时显示该版本的完整内容；未启用编辑历史或没有编辑过时不输出
+func (c *Converter) writeEditHistory(builder *strings.Builder, history github.EditHistory) {
+	if !c.enableEditHistory || len(history.Revisions) < 2 {
+		return
+	}
+
+	builder.WriteString("<details>\n")
+	builder.WriteString(fmt.Sprintf("<summary>编辑历史（%d 次编辑）</summary>\n\n", len(history.Revisions)-1))
+	for i, revision := range history.Revisions {
+		heading := c.formatTimestamp(revision.EditedAt)
+		if revision.Editor.Login != "" {
+			heading += " " + c.formatUser(revision.Editor)
+		}
+		if i == 0 {
+			builder.WriteString(fmt.Sprintf("**原始内容** — %s\n\n", heading))
+			builder.WriteString(codeBlock("markdown", revision.Body))
+		} else if previous := history.Revisions[i-1].Body; diffable(previous, revision.Body) {
+			builder.WriteString(fmt.Sprintf("**编辑** — %s\n\n", heading))
+			builder.WriteString(codeBlock("diff", lineDiff(previous, revision.Body)))
+		} else {
+			builder.WriteString(fmt.Sprintf("**编辑** — %s（内容过长，显示完整内容）\n\n", heading))
+			builder.WriteString(codeBlock("markdown", revision.Body))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("</details>\n\n")
+}
+
+// diffable 判断两个版本能否用 lineDiff 比较（动态规划表不超过 maxDiffCells）
+func diffable(before, after string) bool {
+	return (strings.Count(before, "\n")+1)*(strings.Count(after, "\n")+1) <= maxDiffCells
+}
+
+// lineDiff 按行比较两个版本，返回统一 diff 风格的全文对比（" " 未变、"-" 删除、"+" 新增）
+// 基于最长公共子序列，使用 O(n*m) 的动态规划，调用前须用 diffable 检查大小
+func lineDiff(before, after string) string {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var builder strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			builder.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			builder.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			builder.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return builder.String()
+}
+
+// splitLines 将内容按行拆分（统一换行符，空内容返回空切片）
+func splitLines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
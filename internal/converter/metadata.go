@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// metadataFrontmatter 返回标签、指派、里程碑、关闭信息等 Frontmatter 字段
+// 标签和指派输出为 YAML 列表，便于按标签筛选；空值字段省略，评论数总是输出
+func (c *Converter) metadataFrontmatter(meta github.Metadata) []frontmatterField {
+	fields := []frontmatterField{}
+	if len(meta.Labels) > 0 {
+		fields = append(fields, frontmatterField{key: "labels", value: meta.Labels})
+	}
+	if len(meta.Assignees) > 0 {
+		assignees := make([]string, len(meta.Assignees))
+		for i, assignee := range meta.Assignees {
+			assignees[i] = "@" + assignee.Login
+		}
+		fields = append(fields, frontmatterField{key: "assignees", value: assignees})
+	}
+	if meta.Milestone != "" {
+		fields = append(fields, frontmatterField{key: "milestone", value: meta.Milestone})
+	}
+	if meta.ClosedAt != nil {
+		fields = append(fields, frontmatterField{key: "closed_at", value: c.formatTimestamp(*meta.ClosedAt)})
+	}
+	if meta.ClosedBy.Login != "" {
+		fields = append(fields, frontmatterField{key: "closed_by", value: "@" + meta.ClosedBy.Login})
+	}
+	if meta.StateReason != "" {
+		fields = append(fields, frontmatterField{key: "state_reason", value: meta.StateReason})
+	}
+	if meta.Locked {
+		fields = append(fields, frontmatterField{key: "locked", value: true})
+	}
+	return append(fields, frontmatterField{key: "comments", value: meta.CommentCount})
+}
+
+// writeMetadata 在元数据区输出标签、指派、里程碑、关闭信息和锁定状态
+func (c *Converter) writeMetadata(builder *strings.Builder, meta github.Metadata) {
+	if len(meta.Labels) > 0 {
+		labels := make([]string, len(meta.Labels))
+		for i, label := range meta.Labels {
+			labels[i] = fmt.Sprintf("`%s`", label)
+		}
+		builder.WriteString(fmt.Sprintf("**标签**: %s\n", strings.Join(labels, ", ")))
+	}
+	if len(meta.Assignees) > 0 {
+		assignees := make([]string, len(meta.Assignees))
+		for i, assignee := range meta.Assignees {
+			assignees[i] = c.formatUser(assignee)
+		}
+		builder.WriteString(fmt.Sprintf("**指派**: %s\n", strings.Join(assignees, ", ")))
+	}
+	if meta.Milestone != "" {
+		builder.WriteString(fmt.Sprintf("**里程碑**: %s\n", meta.Milestone))
+	}
+	if meta.ClosedAt != nil {
+		closed := c.formatTimestamp(*meta.ClosedAt)
+		if meta.ClosedBy.Login != "" {
+			closed += fmt.Sprintf("，由 %s 关闭", c.formatUser(meta.ClosedBy))
+		}
+		if label, ok := stateReasonLabels[meta.StateReason]; ok {
+			closed += fmt.Sprintf("（%s）", label)
+		}
+		builder.WriteString(fmt.Sprintf("**关闭时间**: %s\n", closed))
+	}
+	if meta.Locked {
+		builder.WriteString("**已锁定**: 是\n")
+	}
+}
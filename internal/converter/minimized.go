@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// MinimizedPolicy 被隐藏（折叠）评论的处理方式
+type MinimizedPolicy string
+
+const (
+	MinimizedShow     MinimizedPolicy = "show"     // 原样显示，标题中标注隐藏原因
+	MinimizedCollapse MinimizedPolicy = "collapse" // 正文折叠在 <details> 中，摘要显示隐藏原因
+	MinimizedOmit     MinimizedPolicy = "omit"     // 连同其回复一起省略
+)
+
+// minimizedReasonLabels 隐藏原因的显示文本
+var minimizedReasonLabels = map[string]string{
+	"spam":      "垃圾信息",
+	"off-topic": "偏离主题",
+	"outdated":  "已过时",
+	"resolved":  "已解决",
+	"duplicate": "重复",
+	"abuse":     "滥用",
+}
+
+// minimizedReason 返回隐藏原因的显示文本，原因未知时为空
+func minimizedReason(comment github.Comment) string {
+	if reason, ok := minimizedReasonLabels[comment.MinimizedReason]; ok {
+		return reason
+	}
+	return comment.MinimizedReason
+}
+
+// minimizedLabel 返回评论标题中的隐藏标注（如 "已隐藏: 垃圾信息"）
+func minimizedLabel(comment github.Comment) string {
+	if reason := minimizedReason(comment); reason != "" {
+		return "已隐藏: " + reason
+	}
+	return "已隐藏"
+}
+
+// writeMinimizedBody 将被隐藏评论的正文折叠输出，摘要中显示隐藏原因
+func (c *Converter) writeMinimizedBody(builder *strings.Builder, comment github.Comment) {
+	builder.WriteString("<details>\n")
+	summary := "此评论已被隐藏"
+	if reason := minimizedReason(comment); reason != "" {
+		summary += fmt.Sprintf("（%s）", reason)
+	}
+	builder.WriteString(fmt.Sprintf("<summary>%s</summary>\n\n", summary))
+	if comment.Body != "" {
+		builder.WriteString(c.convertEmojiShortcode(comment.Body))
+		builder.WriteString("\n\n")
+	}
+	builder.WriteString("</details>\n\n")
+}
@@ -0,0 +1,218 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// pullRequestFrontmatter 返回 PR 特有的 Frontmatter 字段（分支、草稿、合并信息）
+func (c *Converter) pullRequestFrontmatter(pr *github.PullRequest) []frontmatterField {
+	fields := []frontmatterField{}
+	if pr.BaseRef != "" {
+		fields = append(fields, frontmatterField{key: "base", value: pr.BaseRef})
+	}
+	if pr.HeadRef != "" {
+		fields = append(fields, frontmatterField{key: "head", value: pr.HeadRef})
+	}
+	if pr.HeadSHA != "" {
+		fields = append(fields, frontmatterField{key: "head_sha", value: pr.HeadSHA})
+	}
+	fields = append(fields, frontmatterField{key: "draft", value: pr.Draft})
+	if pr.MergeCommitSHA != "" {
+		fields = append(fields, frontmatterField{key: "merge_commit_sha", value: pr.MergeCommitSHA})
+	}
+	if pr.MergedBy.Login != "" {
+		fields = append(fields, frontmatterField{key: "merged_by", value: "@" + pr.MergedBy.Login})
+	}
+	if pr.MergedAt != nil {
+		fields = append(fields, frontmatterField{key: "merged_at", value: c.formatTimestamp(*pr.MergedAt)})
+	}
+	return append(fields, c.reactionsFrontmatter(pr.Reactions)...)
+}
+
+// writePullRequestMetadata 输出 PR 分支与合并信息
+func (c *Converter) writePullRequestMetadata(builder *strings.Builder, pr *github.PullRequest) {
+	if pr.BaseRef != "" && pr.HeadRef != "" {
+		builder.WriteString(fmt.Sprintf("**分支**: `%s` → `%s`\n", pr.HeadRef, pr.BaseRef))
+	}
+
+	if pr.MergedAt != nil {
+		merged := c.formatTimestamp(*pr.MergedAt)
+		if pr.MergedBy.Login != "" {
+			merged = fmt.Sprintf("%s 于 %s", c.formatUser(pr.MergedBy), merged)
+		}
+		if pr.MergeCommitSHA != "" {
+			merged += fmt.Sprintf("（`%s`）", shortSHA(pr.MergeCommitSHA))
+		}
+		builder.WriteString(fmt.Sprintf("**合并**: %s\n", merged))
+	}
+}
+
+// writeCommits 输出 PR 提交列表（每个提交显示短 SHA、消息首行、作者和时间）
+func (c *Converter) writeCommits(builder *strings.Builder, commits []github.Commit) {
+	builder.WriteString("## 提交记录\n\n")
+	for _, commit := range commits {
+		sha := fmt.Sprintf("`%s`", shortSHA(commit.SHA))
+		if commit.URL != "" {
+			sha = fmt.Sprintf("[%s](%s)", sha, commit.URL)
+		}
+
+		author := commit.AuthorName
+		if commit.Author.Login != "" {
+			author = c.formatUser(commit.Author)
+		}
+
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		builder.WriteString(fmt.Sprintf("- %s %s — %s (%s)\n", sha, subject, author, c.formatTimestamp(commit.Date)))
+	}
+	builder.WriteString("\n")
+}
+
+// checkConclusionIcons CI 检查结论对应的图标
+var checkConclusionIcons = map[string]string{
+	"success":         "✅",
+	"failure":         "❌",
+	"error":           "❌",
+	"timed_out":       "⏱️",
+	"cancelled":       "🚫",
+	"action_required": "⚠️",
+	"neutral":         "⚪",
+	"skipped":         "⏭️",
+	"pending":         "⏳",
+}
+
+// writeChecks 输出 CI 检查表格（名称、结论、耗时、详情链接）
+func (c *Converter) writeChecks(builder *strings.Builder, checks []github.Check) {
+	builder.WriteString("## CI 检查\n\n")
+	builder.WriteString("| 检查 | 结论 | 耗时 | 详情 |\n")
+	builder.WriteString("|------|------|------|------|\n")
+	for _, check := range checks {
+		// 未完成的 check run 没有 conclusion，显示其运行状态
+		conclusion := check.Conclusion
+		if conclusion == "" {
+			conclusion = check.Status
+		}
+		if conclusion == "" {
+			conclusion = "pending"
+		}
+		if icon, ok := checkConclusionIcons[conclusion]; ok {
+			conclusion = icon + " " + conclusion
+		}
+
+		duration := "-"
+		if check.StartedAt != nil && check.CompletedAt != nil {
+			duration = check.CompletedAt.Sub(*check.StartedAt).Round(time.Second).String()
+		}
+
+		details := "-"
+		if check.DetailsURL != "" {
+			details = fmt.Sprintf("[查看](%s)", check.DetailsURL)
+		}
+
+		builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			escapeTableCell(check.Name), conclusion, duration, details))
+	}
+	builder.WriteString("\n")
+}
+
+// shortSHA 返回 7 位短 SHA
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// writeChangedFiles 输出 PR 变更文件表格及 diffstat 汇总
+func (c *Converter) writeChangedFiles(builder *strings.Builder, files []github.ChangedFile) {
+	var additions, deletions int
+	for _, file := range files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+
+	builder.WriteString("## 变更文件\n\n")
+	builder.WriteString(fmt.Sprintf("共 %d 个文件，+%d -%d\n\n", len(files), additions, deletions))
+
+	builder.WriteString("| 文件 | 状态 | 变更 | |\n")
+	builder.WriteString("|------|------|------|---|\n")
+	for _, file := range files {
+		builder.WriteString(fmt.Sprintf("| `%s` | %s | +%d -%d | %s |\n",
+			escapeTableCell(file.Filename), file.Status, file.Additions, file.Deletions,
+			diffstatBar(file.Additions, file.Deletions)))
+	}
+	builder.WriteString("\n")
+}
+
+// writePatches 输出各文件的补丁，超出 patchSizeLimit 的部分截断
+func (c *Converter) writePatches(builder *strings.Builder, files []github.ChangedFile) {
+	builder.WriteString("## 附录：补丁\n\n")
+	for _, file := range files {
+		builder.WriteString(fmt.Sprintf("### `%s`\n\n", file.Filename))
+
+		if file.Patch == "" {
+			builder.WriteString("*（无补丁：二进制文件或变更过大）*\n\n")
+			continue
+		}
+
+		patch, truncated := truncatePatch(file.Patch, c.patchSizeLimit)
+		builder.WriteString(codeBlock("diff", patch))
+		builder.WriteString("\n")
+		if truncated {
+			builder.WriteString(fmt.Sprintf("*（补丁已截断，完整大小 %d 字节）*\n\n", len(file.Patch)))
+		}
+	}
+}
+
+// diffstatBarWidth diffstat 条形图的格数
+const diffstatBarWidth = 5
+
+// diffstatBar 生成 GitHub 风格的 diffstat 条形图（🟩 新增、🟥 删除、⬜ 未变更）
+func diffstatBar(additions, deletions int) string {
+	total := additions + deletions
+	if total == 0 {
+		return strings.Repeat("⬜", diffstatBarWidth)
+	}
+
+	width := diffstatBarWidth
+	if total < width {
+		width = total
+	}
+	added := additions * width / total
+	if additions > 0 && added == 0 {
+		added = 1
+	}
+	deleted := width - added
+	if deletions == 0 {
+		deleted = 0
+	}
+
+	return strings.Repeat("🟩", added) + strings.Repeat("🟥", deleted) +
+		strings.Repeat("⬜", diffstatBarWidth-added-deleted)
+}
+
+// truncatePatch 按字节数截断补丁，截断点回退到最近的行尾，避免截断半行或多字节字符
+// limit <= 0 表示不限制
+func truncatePatch(patch string, limit int) (string, bool) {
+	if limit <= 0 || len(patch) <= limit {
+		return patch, false
+	}
+
+	cut := strings.LastIndex(patch[:limit], "\n")
+	if cut <= 0 {
+		cut = limit
+		for cut > 0 && !utf8.RuneStart(patch[cut]) {
+			cut--
+		}
+	}
+	return patch[:cut], true
+}
+
+// escapeTableCell 转义 Markdown 表格单元格中的竖线
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,66 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// formatIssueRef 格式化关联 Issue 为 "#编号 标题" 链接
+func formatIssueRef(ref github.IssueRef) string {
+	text := fmt.Sprintf("#%d %s", ref.Number, ref.Title)
+	if ref.URL == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, ref.URL)
+}
+
+// completionPercent 返回完成百分比（向下取整），total 为 0 时返回 0
+func completionPercent(completed, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return completed * 100 / total
+}
+
+// trackingFrontmatter 返回父 Issue 和完成度的 Frontmatter 字段
+// 完成度统计子 Issue 和正文中的任务列表项，两者都没有时省略
+func (c *Converter) trackingFrontmatter(issue *github.Issue) []frontmatterField {
+	fields := []frontmatterField{}
+	if issue.Parent != nil && issue.Parent.URL != "" {
+		fields = append(fields, frontmatterField{key: "parent_issue", value: issue.Parent.URL})
+	}
+	if completed, total := issue.Progress(); total > 0 {
+		fields = append(fields, frontmatterField{key: "completion", value: completionPercent(completed, total)})
+	}
+	return fields
+}
+
+// writeTrackingMetadata 在元数据区输出父 Issue 和完成进度
+func (c *Converter) writeTrackingMetadata(builder *strings.Builder, issue *github.Issue) {
+	if issue.Parent != nil {
+		builder.WriteString(fmt.Sprintf("**父 Issue**: %s\n", formatIssueRef(*issue.Parent)))
+	}
+	if completed, total := issue.Progress(); total > 0 {
+		builder.WriteString(fmt.Sprintf("**进度**: %d/%d（%d%%）\n", completed, total, completionPercent(completed, total)))
+	}
+}
+
+// writeSubIssues 输出子 Issue 列表：复选框表示是否已关闭，附带状态和关闭原因
+func (c *Converter) writeSubIssues(builder *strings.Builder, subIssues []github.IssueRef) {
+	builder.WriteString("## 子 Issue\n\n")
+	for _, subIssue := range subIssues {
+		checkbox := "[ ]"
+		if subIssue.State == "closed" {
+			checkbox = "[x]"
+		}
+
+		state := title(subIssue.State)
+		if label, ok := stateReasonLabels[subIssue.StateReason]; ok && subIssue.State == "closed" {
+			state += fmt.Sprintf("（%s）", label)
+		}
+		builder.WriteString(fmt.Sprintf("- %s %s — %s\n", checkbox, formatIssueRef(subIssue), state))
+	}
+	builder.WriteString("\n")
+}
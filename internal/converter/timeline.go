@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wuwenrufeng/issue2md/internal/github"
+)
+
+// stateReasonLabels 关闭原因的显示文本（Issue 和 Discussion）
+var stateReasonLabels = map[string]string{
+	"completed":   "已完成",
+	"not_planned": "不计划处理",
+	"duplicate":   "重复",
+	"resolved":    "已解决",
+	"outdated":    "已过时",
+}
+
+// writeIssueTimeline 按时间顺序交错输出评论和时间线事件
+// 连续的事件合并为一个紧凑列表，评论仍按原格式输出
+func (c *Converter) writeIssueTimeline(builder *strings.Builder, comments []github.Comment, events []github.TimelineEvent) {
+	i, j := 0, 0
+	inList := false
+	for i < len(comments) || j < len(events) {
+		// 时间相同时事件在前（如关闭操作与关闭评论）
+		if j < len(events) && (i >= len(comments) || !comments[i].CreatedAt.Before(events[j].CreatedAt)) {
+			if line := c.formatEvent(events[j]); line != "" {
+				builder.WriteString("- " + line + "\n")
+				inList = true
+			}
+			j++
+			continue
+		}
+
+		// 省略的评论不打断事件列表
+		if c.skipComment(comments[i]) {
+			i++
+			continue
+		}
+		if inList {
+			builder.WriteString("\n")
+			inList = false
+		}
+		c.writeComment(builder, comments[i], 3)
+		i++
+	}
+	if inList {
+		builder.WriteString("\n")
+	}
+}
+
+// formatEvent 将事件格式化为一行描述，不支持的事件返回空字符串
+func (c *Converter) formatEvent(event github.TimelineEvent) string {
+	var action string
+	switch event.Type {
+	case github.EventLabeled:
+		action = fmt.Sprintf("添加了标签 `%s`", event.Label)
+	case github.EventUnlabeled:
+		action = fmt.Sprintf("移除了标签 `%s`", event.Label)
+	case github.EventAssigned:
+		action = fmt.Sprintf("指派给 %s", c.formatUser(event.Assignee))
+	case github.EventUnassigned:
+		action = fmt.Sprintf("取消指派 %s", c.formatUser(event.Assignee))
+	case github.EventMilestoned:
+		action = fmt.Sprintf("添加到里程碑 `%s`", event.Milestone)
+	case github.EventDemilestoned:
+		action = fmt.Sprintf("从里程碑 `%s` 中移除", event.Milestone)
+	case github.EventRenamed:
+		action = fmt.Sprintf("将标题从 “%s” 修改为 “%s”", event.RenameFrom, event.RenameTo)
+	case github.EventClosed:
+		action = "关闭了此 Issue"
+		if label, ok := stateReasonLabels[event.StateReason]; ok {
+			action += fmt.Sprintf("（%s）", label)
+		}
+		if event.CommitSHA != "" {
+			action += fmt.Sprintf("，提交 `%s`", shortSHA(event.CommitSHA))
+		}
+	case github.EventReopened:
+		action = "重新打开了此 Issue"
+	case github.EventReferenced:
+		action = fmt.Sprintf("在提交 `%s` 中引用了此 Issue", shortSHA(event.CommitSHA))
+	case github.EventCrossReferenced:
+		source := fmt.Sprintf("#%d %s", event.SourceNumber, event.SourceTitle)
+		if event.SourceURL != "" {
+			source = fmt.Sprintf("[%s](%s)", source, event.SourceURL)
+		}
+		action = fmt.Sprintf("在 %s 中引用了此 Issue", source)
+	default:
+		return ""
+	}
+
+	actor := "某用户"
+	if event.Actor.Login != "" {
+		actor = c.formatUser(event.Actor)
+	}
+	return fmt.Sprintf("%s %s — %s", actor, action, c.formatTimestamp(event.CreatedAt))
+}
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cachedHeaders 随响应体一起缓存的响应头（Link 用于分页，其余用于条件请求）
+var cachedHeaders = []string{"Link", "ETag", "Last-Modified"}
+
+// Cache 磁盘 HTTP 响应缓存
+//
+// 缓存 GET 响应，再次请求同一 URL 时携带 If-None-Match / If-Modified-Since，
+// 服务器返回 304 时使用缓存内容（GitHub 不将 304 计入限额）。
+// 存入时间在 TTL 内的条目直接使用，不发送请求。
+type Cache struct {
+	dir string           // 缓存目录
+	ttl time.Duration    // 条目免校验的有效期，0 表示总是发送条件请求
+	now func() time.Time // 当前时间（测试时替换）
+}
+
+// cacheEntry 缓存文件内容
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// NewCache 创建使用 dir 目录的缓存
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// DefaultCacheDir 返回默认缓存目录（用户缓存目录下的 issue2md）
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate user cache dir: %w", err)
+	}
+	return filepath.Join(dir, "issue2md"), nil
+}
+
+// Dir 返回缓存目录
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Clear 删除全部缓存
+// 只删除缓存自身写入的文件（条目和残留的临时文件），目录中的其他文件保持不变
+func (c *Cache) Clear() error {
+	files, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("clear cache: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !isCacheFile(file.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, file.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// isCacheFile 判断文件名是否为缓存条目（64 位十六进制键 + .json）或写入条目时的临时文件
+func isCacheFile(name string) bool {
+	if matched, _ := filepath.Match("entry-*.tmp", name); matched {
+		return true
+	}
+	key, ok := strings.CutSuffix(name, ".json")
+	if !ok || len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
+
+// key 根据 URL 和 Token 计算缓存键，不同 Token 的响应互不共享（私有仓库内容不会泄露给其他 Token）
+func (c *Cache) key(url, token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	sum := sha256.Sum256([]byte(url + "\n" + hex.EncodeToString(tokenHash[:])))
+	return hex.EncodeToString(sum[:])
+}
+
+// path 返回缓存条目的文件路径
+func (c *Cache) path(url, token string) string {
+	return filepath.Join(c.dir, c.key(url, token)+".json")
+}
+
+// load 读取缓存条目，不存在或无法解析时返回 nil
+func (c *Cache) load(url, token string) *cacheEntry {
+	data, err := os.ReadFile(c.path(url, token))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// store 写入缓存条目
+func (c *Cache) store(url, token string, header http.Header, body []byte) error {
+	entry := cacheEntry{
+		URL:      url,
+		Header:   http.Header{},
+		Body:     body,
+		StoredAt: c.now(),
+	}
+	for _, name := range cachedHeaders {
+		if value := header.Get(name); value != "" {
+			entry.Header.Set(name, value)
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免并发读取到写了一半的条目
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(url, token)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// fresh 判断条目是否仍在 TTL 内，可以不发请求直接使用
+func (c *Cache) fresh(entry *cacheEntry) bool {
+	return c.ttl > 0 && c.now().Sub(entry.StoredAt) < c.ttl
+}
+
+// setConditionalHeaders 为请求添加条件请求头
+func (entry *cacheEntry) setConditionalHeaders(req *http.Request) {
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+}
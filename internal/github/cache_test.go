@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCache_ConditionalRequests 测试缓存写入、条件请求、304 命中和 TTL
+func TestCache_ConditionalRequests(t *testing.T) {
+	var requests, notModified int
+	var lastIfNoneMatch string
+
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		lastIfNoneMatch = r.Header.Get("If-None-Match")
+		etag := `"v1-` + r.URL.Query().Get("page") + `"`
+		if lastIfNoneMatch == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+mockServer.URL+`/items?page=2>; rel="next"`)
+			w.Write([]byte(`[1, 2]`))
+			return
+		}
+		w.Write([]byte(`[3]`))
+	}))
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	now := time.Unix(1700000000, 0)
+	cache := NewCache(dir, 0)
+	cache.now = func() time.Time { return now }
+
+	fetch := func(token string) []int {
+		t.Helper()
+		client := NewClient(token, WithCache(cache))
+		items, err := getAll[int](context.Background(), client, mockServer.URL+"/items")
+		if err != nil {
+			t.Fatalf("getAll failed: %v", err)
+		}
+		return items
+	}
+
+	// 首次请求：两页都写入缓存
+	if items := fetch("token-a"); len(items) != 3 {
+		t.Fatalf("expected 3 items, got %v", items)
+	}
+	if requests != 2 || notModified != 0 {
+		t.Fatalf("expected 2 uncached requests, got %d (%d not modified)", requests, notModified)
+	}
+
+	// 再次请求：发送条件请求，304 时使用缓存（含 Link 头，分页仍然完整）
+	if items := fetch("token-a"); len(items) != 3 {
+		t.Fatalf("expected 3 items from cache, got %v", items)
+	}
+	if requests != 4 || notModified != 2 {
+		t.Errorf("expected 2 conditional requests answered with 304, got %d requests (%d not modified)", requests, notModified)
+	}
+
+	// 不同 Token 不共享缓存
+	fetch("token-b")
+	if lastIfNoneMatch != "" {
+		t.Errorf("expected no conditional header for a different token, got %q", lastIfNoneMatch)
+	}
+
+	// TTL 内不发送请求
+	cache.ttl = time.Hour
+	requests = 0
+	now = now.Add(30 * time.Minute)
+	if items := fetch("token-a"); len(items) != 3 || requests != 0 {
+		t.Errorf("expected fresh cache hit without requests, got %v after %d requests", items, requests)
+	}
+
+	// 超过 TTL 后重新校验
+	now = now.Add(2 * time.Hour)
+	fetch("token-a")
+	if requests != 2 {
+		t.Errorf("expected revalidation after TTL, got %d requests", requests)
+	}
+
+	// 清空缓存
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if entries, err := filepath.Glob(filepath.Join(dir, "*.json")); err != nil || len(entries) != 0 {
+		t.Errorf("expected cache entries to be removed, got %v (%v)", entries, err)
+	}
+}
+
+// TestCache_SkipsErrorsAndGraphQL 测试错误响应和 GraphQL POST 不会被缓存
+func TestCache_SkipsErrorsAndGraphQL(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	client := NewClient("", WithBaseURL(mockServer.URL), WithCache(NewCache(dir, time.Hour)))
+
+	var v map[string]interface{}
+	client.get(context.Background(), mockServer.URL+"/missing", &v)
+	client.graphQL(context.Background(), "query { viewer { login } }", nil, &v)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no cache entries, got %d", len(entries))
+	}
+}
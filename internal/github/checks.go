@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// fetchChecks 获取提交上的 CI 检查：先 check runs，再 commit statuses
+// 任一接口获取失败时跳过该部分
+func (c *Client) fetchChecks(ctx context.Context, owner, repo, sha string) []Check {
+	checks := []Check{}
+
+	// Check runs（GitHub Actions 等）
+	checkRunsURL := fmt.Sprintf("%s/repos/%s/%s/commits/%s/check-runs", c.baseURL, owner, repo, sha)
+	var checkRuns []Check
+	err := c.paginate(ctx, checkRunsURL, func(body []byte) error {
+		var page struct {
+			CheckRuns []struct {
+				Name        string     `json:"name"`
+				Status      string     `json:"status"`
+				Conclusion  string     `json:"conclusion"`
+				StartedAt   *time.Time `json:"started_at"`
+				CompletedAt *time.Time `json:"completed_at"`
+				DetailsURL  string     `json:"details_url"`
+				HTMLURL     string     `json:"html_url"`
+			} `json:"check_runs"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+
+		for _, run := range page.CheckRuns {
+			detailsURL := run.DetailsURL
+			if detailsURL == "" {
+				detailsURL = run.HTMLURL
+			}
+			checkRuns = append(checkRuns, Check{
+				Name:        run.Name,
+				Kind:        CheckKindCheckRun,
+				Status:      run.Status,
+				Conclusion:  run.Conclusion,
+				StartedAt:   run.StartedAt,
+				CompletedAt: run.CompletedAt,
+				DetailsURL:  detailsURL,
+			})
+		}
+		return nil
+	})
+	if err == nil {
+		checks = append(checks, checkRuns...)
+	}
+
+	// Commit statuses（外部 CI），combined status 中每个 context 只保留最新状态
+	statusURL := fmt.Sprintf("%s/repos/%s/%s/commits/%s/status", c.baseURL, owner, repo, sha)
+	var statuses []Check
+	err = c.paginate(ctx, statusURL, func(body []byte) error {
+		var page struct {
+			Statuses []struct {
+				Context   string `json:"context"`
+				State     string `json:"state"`
+				TargetURL string `json:"target_url"`
+			} `json:"statuses"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+
+		for _, status := range page.Statuses {
+			statuses = append(statuses, Check{
+				Name:       status.Context,
+				Kind:       CheckKindStatus,
+				Conclusion: status.State,
+				DetailsURL: status.TargetURL,
+			})
+		}
+		return nil
+	})
+	if err == nil {
+		checks = append(checks, statuses...)
+	}
+
+	return checks
+}
@@ -2,11 +2,16 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/rand/v2"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -14,34 +19,133 @@ import (
 var (
 	ErrResourceNotFound = errors.New("resource not found")
 	ErrAPIRateLimit     = errors.New("API rate limit exceeded")
+	ErrForbidden        = errors.New("forbidden")
 	ErrNetwork          = errors.New("network error")
 )
 
+// 默认的 GitHub 主机和 API 地址
+const (
+	defaultHost    = "github.com"
+	defaultBaseURL = "https://api.github.com"
+)
+
 // Option 配置 Client 的选项
 type Option func(*Client)
 
-// WithBaseURL 设置自定义的 BaseURL（用于测试）
+// WithBaseURL 设置自定义的 BaseURL（用于测试），GraphQL 端点为 BaseURL 下的 /graphql
 func WithBaseURL(baseURL string) Option {
 	return func(c *Client) {
 		c.baseURL = baseURL
+		c.graphqlURL = baseURL + "/graphql"
+	}
+}
+
+// WithHost 按 GitHub 主机名设置 API 地址
+//   - github.com：https://api.github.com 和 https://api.github.com/graphql
+//   - GitHub Enterprise Server：https://{host}/api/v3 和 https://{host}/api/graphql
+func WithHost(host string) Option {
+	return func(c *Client) {
+		c.baseURL, c.graphqlURL = apiURLs(host)
+	}
+}
+
+// apiURLs 返回主机对应的 REST API 根地址和 GraphQL 端点
+func apiURLs(host string) (restURL, graphqlURL string) {
+	host = strings.ToLower(host)
+	if host == "" || host == defaultHost {
+		return defaultBaseURL, defaultBaseURL + "/graphql"
+	}
+	return "https://" + host + "/api/v3", "https://" + host + "/api/graphql"
+}
+
+// WithPerPage 设置列表接口每页请求的条目数（GitHub 允许 1-100）
+func WithPerPage(perPage int) Option {
+	return func(c *Client) {
+		if perPage < 1 {
+			perPage = 1
+		}
+		if perPage > maxPerPage {
+			perPage = maxPerPage
+		}
+		c.perPage = perPage
+	}
+}
+
+// WithRateLimitWait 设置触发限流时自动等待的最长时间
+// 需要等待的时间不超过 maxWait 时休眠到限额重置后重试，否则直接返回 RateLimitError；0 表示不等待
+func WithRateLimitWait(maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.rateLimitWait = maxWait
+	}
+}
+
+// WithRetryPolicy 设置瞬时故障的重试策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithLogger 设置详细日志输出（重试、限流等待等），nil 表示不输出
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithCache 设置磁盘 HTTP 缓存，nil 表示不缓存
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithTimelineEvents 设置 FetchIssue 是否获取时间线事件（需要额外的 API 请求）
+func WithTimelineEvents(enable bool) Option {
+	return func(c *Client) {
+		c.timelineEvents = enable
+	}
+}
+
+// WithEditHistory 设置是否获取正文和评论的全部历史版本（GraphQL userContentEdits，需要 Token）
+func WithEditHistory(enable bool) Option {
+	return func(c *Client) {
+		c.editHistory = enable
 	}
 }
 
 // Client GitHub API 客户端
 type Client struct {
-	baseURL string       // API Base URL
-	token   string       // GitHub Token（可选）
-	client  *http.Client // HTTP 客户端
+	baseURL        string                                     // API Base URL
+	graphqlURL     string                                     // GraphQL 端点
+	token          string                                     // GitHub Token（可选）
+	client         *http.Client                               // HTTP 客户端
+	perPage        int                                        // 列表接口每页条目数
+	rateLimitWait  time.Duration                              // 限流时自动等待的最长时间
+	retry          RetryPolicy                                // 瞬时故障的重试策略
+	timelineEvents bool                                       // FetchIssue 是否获取时间线事件
+	editHistory    bool                                       // 是否获取编辑历史版本
+	cache          *Cache                                     // 磁盘 HTTP 缓存（可选）
+	logger         *log.Logger                                // 详细日志（可选）
+	random         func() float64                             // 随机数（用于退避抖动，测试时替换）
+	sleep          func(context.Context, time.Duration) error // 等待函数（测试时替换）
+	now            func() time.Time                           // 当前时间（测试时替换）
 }
 
 // NewClient 创建新的 GitHub Client
 func NewClient(token string, opts ...Option) *Client {
 	client := &Client{
-		baseURL: "https://api.github.com", // 默认 GitHub API URL
-		token:   token,
+		baseURL:    defaultBaseURL, // 默认 GitHub API URL
+		graphqlURL: defaultBaseURL + "/graphql",
+		token:      token,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		perPage: maxPerPage,
+		retry:   DefaultRetryPolicy(),
+		sleep:   sleepContext,
+		now:     time.Now,
+		random:  rand.Float64,
 	}
 
 	// 应用选项
@@ -54,92 +158,129 @@ func NewClient(token string, opts ...Option) *Client {
 
 // FetchIssue 获取 GitHub Issue
 func (c *Client) FetchIssue(owner, repo string, number int) (*Issue, error) {
+	return c.FetchIssueContext(context.Background(), owner, repo, number)
+}
+
+// FetchIssueContext 获取 GitHub Issue，ctx 取消时中止进行中的请求
+func (c *Client) FetchIssueContext(ctx context.Context, owner, repo string, number int) (*Issue, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
 
 	// 获取 Issue 主数据
 	var issueData struct {
-		Title   string `json:"title"`
-		HTMLURL string `json:"html_url"`
-		User    struct {
-			Login   string `json:"login"`
-			HTMLURL string `json:"html_url"`
-		} `json:"user"`
-		CreatedAt time.Time `json:"created_at"`
-		State     string    `json:"state"`
-		Body      string    `json:"body"`
-	}
-
-	err := c.get(url, &issueData)
+		NodeID            string                `json:"node_id"`
+		Title             string                `json:"title"`
+		HTMLURL           string                `json:"html_url"`
+		User              restUser              `json:"user"`
+		AuthorAssociation AuthorAssociation     `json:"author_association"`
+		CreatedAt         time.Time             `json:"created_at"`
+		State             string                `json:"state"`
+		Body              string                `json:"body"`
+		Reactions         restReactions         `json:"reactions"`
+		SubIssuesSummary  *restSubIssuesSummary `json:"sub_issues_summary"`
+		ParentIssueURL    string                `json:"parent_issue_url"`
+		restIssueMetadata
+	}
+
+	err := c.get(ctx, url, &issueData)
 	if err != nil {
 		return nil, err
 	}
 
 	// 构建Issue
 	issue := &Issue{
-		Title: issueData.Title,
-		URL:   issueData.HTMLURL,
-		User: User{
-			Login:   issueData.User.Login,
-			HTMLURL: issueData.User.HTMLURL,
-		},
-		CreatedAt: issueData.CreatedAt,
-		State:     issueData.State,
-		Body:      issueData.Body,
-	}
-
-	// 获取评论
+		Title:             issueData.Title,
+		URL:               issueData.HTMLURL,
+		User:              issueData.User.toUser(),
+		AuthorAssociation: issueData.AuthorAssociation,
+		CreatedAt:         issueData.CreatedAt,
+		State:             issueData.State,
+		Metadata:          issueData.toMetadata(),
+		Body:              issueData.Body,
+		Reactions:         buildReactions(issueData.Reactions),
+		Tasks:             parseTasks(issueData.Body),
+	}
+
+	// 获取评论（跟随分页获取全部）
 	commentsURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, number)
-	var commentsData []struct {
-		ID   int64 `json:"id"`
-		User struct {
-			Login   string `json:"login"`
-			HTMLURL string `json:"html_url"`
-		} `json:"user"`
-		CreatedAt time.Time `json:"created_at"`
-		Body      string    `json:"body"`
-		Reactions struct {
-			TotalCount int `json:"total_count"`
-			PlusOne    int `json:"plus_one"`
-			Heart      int `json:"heart"`
-		} `json:"reactions"`
-	}
-
-	err = c.get(commentsURL, &commentsData)
+	commentsData, err := getAll[restComment](ctx, c, commentsURL)
 	if err == nil {
 		issue.Comments = make([]Comment, len(commentsData))
 		for i, cData := range commentsData {
-			issue.Comments[i] = Comment{
-				ID:        cData.ID,
-				User:      User{Login: cData.User.Login, HTMLURL: cData.User.HTMLURL},
-				CreatedAt: cData.CreatedAt,
-				Body:      cData.Body,
-				Reactions: buildReactions(cData.Reactions),
-				Deleted:   false,
-			}
+			issue.Comments[i] = cData.toComment(CommentKindIssue)
+		}
+	}
+
+	// 编辑信息和评论的隐藏状态只能通过 GraphQL 获取（需要 Token），失败时忽略
+	if nodes, err := c.fetchContentNodes(ctx, append([]string{issueData.NodeID}, commentNodeIDs(issue.Comments)...)); err == nil {
+		if node, ok := nodes[issueData.NodeID]; ok {
+			issue.EditHistory = node.toEditHistory()
+		}
+		applyContentNodes(issue.Comments, nodes)
+	}
+
+	// 子 Issue 与父 Issue，失败时忽略
+	// 统计显示没有子 Issue 时跳过请求，旧版 GHES 不返回统计时仍尝试获取
+	if issueData.SubIssuesSummary == nil || issueData.SubIssuesSummary.Total > 0 {
+		if subIssues, err := c.fetchSubIssues(ctx, owner, repo, number); err == nil {
+			issue.SubIssues = subIssues
+		}
+	}
+	// 只有 parent_issue_url 不为空时才有父 Issue（不支持子 Issue 的 GHES 不返回该字段）
+	if issueData.ParentIssueURL != "" {
+		if parent, err := c.fetchParentIssue(ctx, owner, repo, number); err == nil {
+			issue.Parent = parent
+		}
+	}
+
+	// 获取时间线事件（可选），失败时忽略
+	if c.timelineEvents {
+		if events, err := c.fetchTimelineEvents(ctx, owner, repo, number); err == nil {
+			issue.Events = events
 		}
 	}
 
+	// 评论和事件获取失败时会被忽略，取消时不能返回不完整的结果
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	return issue, nil
 }
 
 // FetchPullRequest 获取 GitHub Pull Request
 func (c *Client) FetchPullRequest(owner, repo string, number int) (*PullRequest, error) {
+	return c.FetchPullRequestContext(context.Background(), owner, repo, number)
+}
+
+// FetchPullRequestContext 获取 GitHub Pull Request，ctx 取消时中止进行中的请求
+func (c *Client) FetchPullRequestContext(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, owner, repo, number)
 
 	var prData struct {
-		Title   string `json:"title"`
-		HTMLURL string `json:"html_url"`
-		User    struct {
-			Login   string `json:"login"`
-			HTMLURL string `json:"html_url"`
-		} `json:"user"`
-		CreatedAt time.Time `json:"created_at"`
-		State     string    `json:"state"`
-		Body      string    `json:"body"`
-		Merged    bool      `json:"merged"`
-	}
-
-	err := c.get(url, &prData)
+		NodeID            string            `json:"node_id"`
+		Title             string            `json:"title"`
+		HTMLURL           string            `json:"html_url"`
+		User              restUser          `json:"user"`
+		AuthorAssociation AuthorAssociation `json:"author_association"`
+		CreatedAt         time.Time         `json:"created_at"`
+		State             string            `json:"state"`
+		Body              string            `json:"body"`
+		Merged            bool              `json:"merged"`
+		Draft             bool              `json:"draft"`
+		MergeCommitSHA    string            `json:"merge_commit_sha"`
+		MergedBy          *restUser         `json:"merged_by"`
+		MergedAt          *time.Time        `json:"merged_at"`
+		Base              struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		Head struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+		restIssueMetadata
+	}
+
+	err := c.get(ctx, url, &prData)
 	if err != nil {
 		return nil, err
 	}
@@ -151,202 +292,226 @@ func (c *Client) FetchPullRequest(owner, repo string, number int) (*PullRequest,
 	}
 
 	pr := &PullRequest{
-		Title: prData.Title,
-		URL:   prData.HTMLURL,
-		User: User{
-			Login:   prData.User.Login,
-			HTMLURL: prData.User.HTMLURL,
-		},
-		CreatedAt: prData.CreatedAt,
-		State:     state,
-		Body:      prData.Body,
+		Title:             prData.Title,
+		URL:               prData.HTMLURL,
+		User:              prData.User.toUser(),
+		AuthorAssociation: prData.AuthorAssociation,
+		CreatedAt:         prData.CreatedAt,
+		State:             state,
+		Metadata:          prData.toMetadata(),
+		Body:              prData.Body,
+		Draft:             prData.Draft,
+		BaseRef:           prData.Base.Ref,
+		HeadRef:           prData.Head.Ref,
+		HeadSHA:           prData.Head.SHA,
+	}
+
+	// 合并信息：merge_commit_sha 在未合并时是测试合并提交，仅在已合并时记录
+	if prData.Merged {
+		pr.MergeCommitSHA = prData.MergeCommitSHA
+		pr.MergedAt = prData.MergedAt
+		if prData.MergedBy != nil {
+			pr.MergedBy = prData.MergedBy.toUser()
+		}
 	}
 
-	// 获取评论（Review 评论）
-	commentsURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/comments", c.baseURL, owner, repo, number)
-	var commentsData []struct {
-		ID   int64 `json:"id"`
-		User struct {
-			Login   string `json:"login"`
-			HTMLURL string `json:"html_url"`
-		} `json:"user"`
-		CreatedAt time.Time `json:"created_at"`
-		Body      string    `json:"body"`
+	// PR 接口不返回正文的 reactions、关闭者和关闭原因，需从对应的 Issue 接口获取，失败时忽略
+	issueURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
+	var issueData struct {
+		Reactions   restReactions `json:"reactions"`
+		ClosedBy    *restUser     `json:"closed_by"`
+		StateReason string        `json:"state_reason"`
+	}
+	if err := c.get(ctx, issueURL, &issueData); err == nil {
+		pr.Reactions = buildReactions(issueData.Reactions)
+		pr.StateReason = issueData.StateReason
+		if issueData.ClosedBy != nil {
+			pr.ClosedBy = issueData.ClosedBy.toUser()
+		}
 	}
 
-	err = c.get(commentsURL, &commentsData)
-	if err == nil {
-		pr.Comments = make([]Comment, len(commentsData))
-		for i, cData := range commentsData {
-			pr.Comments[i] = Comment{
-				ID:        cData.ID,
-				User:      User{Login: cData.User.Login, HTMLURL: cData.User.HTMLURL},
-				CreatedAt: cData.CreatedAt,
-				Body:      cData.Body,
-				Reactions: []Reaction{},
-				Deleted:   false,
+	// 获取时间线：对话评论和 Review（含其行级评论），合并后按时间排序
+	pr.Comments = c.fetchPullRequestThread(ctx, owner, repo, number)
+
+	// 代码会话的解决/过时状态只能通过 GraphQL 获取（需要 Token），失败时忽略
+	if states, err := c.fetchReviewThreadStates(ctx, owner, repo, number); err == nil {
+		applyReviewThreadStates(pr.Comments, states)
+	}
+
+	// 正文和评论的编辑信息以及评论的隐藏状态，失败时忽略
+	if nodes, err := c.fetchContentNodes(ctx, append([]string{prData.NodeID}, commentNodeIDs(pr.Comments)...)); err == nil {
+		if node, ok := nodes[prData.NodeID]; ok {
+			pr.EditHistory = node.toEditHistory()
+		}
+		applyContentNodes(pr.Comments, nodes)
+	}
+
+	// 获取提交列表
+	commitsURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/commits", c.baseURL, owner, repo, number)
+	if commitsData, err := getAll[restCommit](ctx, c, commitsURL); err == nil {
+		pr.Commits = make([]Commit, len(commitsData))
+		for i, cData := range commitsData {
+			pr.Commits[i] = Commit{
+				SHA:        cData.SHA,
+				URL:        cData.HTMLURL,
+				Message:    cData.Commit.Message,
+				AuthorName: cData.Commit.Author.Name,
+				Date:       cData.Commit.Author.Date,
+			}
+			if cData.Author != nil {
+				pr.Commits[i].Author = cData.Author.toUser()
 			}
 		}
 	}
 
-	return pr, nil
-}
+	// 获取 HEAD 提交上的 CI 检查
+	if pr.HeadSHA != "" {
+		pr.Checks = c.fetchChecks(ctx, owner, repo, pr.HeadSHA)
+	}
 
-// FetchDiscussion 获取 GitHub Discussion（使用 GraphQL）
-func (c *Client) FetchDiscussion(owner, repo string, number int) (*Discussion, error) {
-	// GraphQL 查询
-	query := fmt.Sprintf(`{
-		repository(owner: "%s", name: "%s") {
-			discussion(number: %d) {
-				title
-				url
-				author {
-					login
-					url
-				}
-				createdAt
-				closedAt
-				body
-				comments(first: 100) {
-					nodes {
-						id
-						author {
-							login
-							url
-						}
-						createdAt
-						body
-						reactions(first: 100) {
-							nodes {
-								content
-								users {
-									totalCount
-								}
-							}
-						}
-					}
-				}
+	// 获取变更文件列表
+	filesURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/files", c.baseURL, owner, repo, number)
+	if filesData, err := getAll[restFile](ctx, c, filesURL); err == nil {
+		pr.Files = make([]ChangedFile, len(filesData))
+		for i, fData := range filesData {
+			pr.Files[i] = ChangedFile{
+				Filename:  fData.Filename,
+				Status:    fData.Status,
+				Additions: fData.Additions,
+				Deletions: fData.Deletions,
+				Patch:     fData.Patch,
 			}
 		}
-	}`, owner, repo, number)
-
-	url := c.baseURL + "/graphql"
-
-	var response struct {
-		Data struct {
-			Repository struct {
-				Discussion *struct {
-					Title  string `json:"title"`
-					URL    string `json:"url"`
-					Author struct {
-						Login string `json:"login"`
-						URL   string `json:"url"`
-					} `json:"author"`
-					CreatedAt time.Time  `json:"createdAt"`
-					ClosedAt  *time.Time `json:"closedAt"`
-					Body      string     `json:"body"`
-					Comments  struct {
-						Nodes []struct {
-							ID     string `json:"id"`
-							Author struct {
-								Login string `json:"login"`
-								URL   string `json:"url"`
-							} `json:"author"`
-							CreatedAt time.Time `json:"createdAt"`
-							Body      string    `json:"body"`
-							Reactions struct {
-								Nodes []struct {
-									Content string `json:"content"`
-									Users   struct {
-										TotalCount int `json:"totalCount"`
-									} `json:"users"`
-								} `json:"nodes"`
-							} `json:"reactions"`
-						} `json:"nodes"`
-					} `json:"comments"`
-				} `json:"discussion"`
-			} `json:"repository"`
-		} `json:"data"`
-	}
-
-	err := c.postGraphQL(url, query, &response)
-	if err != nil {
-		return nil, err
 	}
 
-	if response.Data.Repository.Discussion == nil {
-		return nil, ErrResourceNotFound
+	// 附加数据获取失败时会被忽略，取消时不能返回不完整的结果
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	d := response.Data.Repository.Discussion
+	return pr, nil
+}
 
-	// 确定状态
-	state := "open"
-	if d.ClosedAt != nil {
-		state = "closed"
+// fetchPullRequestThread 获取 PR 的完整讨论时间线
+// 任一列表获取失败时跳过该部分，不影响 PR 主体数据
+func (c *Client) fetchPullRequestThread(ctx context.Context, owner, repo string, number int) []Comment {
+	thread := []Comment{}
+
+	// 对话评论（PR 主讨论区，与 Issue 评论共用接口）
+	issueCommentsURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, number)
+	if issueComments, err := getAll[restComment](ctx, c, issueCommentsURL); err == nil {
+		for _, cData := range issueComments {
+			thread = append(thread, cData.toComment(CommentKindIssue))
+		}
 	}
 
-	// 构建评论
-	comments := make([]Comment, len(d.Comments.Nodes))
-	for i, node := range d.Comments.Nodes {
-		reactions := make([]Reaction, len(node.Reactions.Nodes))
-		for j, rNode := range node.Reactions.Nodes {
-			reactions[j] = Reaction{
-				Content: convertReactionContent(rNode.Content),
-				Count:   rNode.Users.TotalCount,
+	// Review（仅保留已提交的 Review，PENDING 状态的草稿没有 submitted_at）
+	reviewsURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/reviews", c.baseURL, owner, repo, number)
+	reviews := []Comment{}
+	reviewIndex := make(map[int64]int)
+	if reviewsData, err := getAll[restReview](ctx, c, reviewsURL); err == nil {
+		for _, rData := range reviewsData {
+			if rData.SubmittedAt == nil {
+				continue
 			}
+			reviewIndex[rData.ID] = len(reviews)
+			reviews = append(reviews, Comment{
+				ID:                rData.ID,
+				NodeID:            rData.NodeID,
+				Kind:              CommentKindReview,
+				User:              rData.User.toUser(),
+				AuthorAssociation: rData.AuthorAssociation,
+				CreatedAt:         *rData.SubmittedAt,
+				Body:              rData.Body,
+				Reactions:         []Reaction{},
+				ReviewState:       ReviewState(rData.State),
+			})
 		}
+	}
 
-		comments[i] = Comment{
-			ID:        0, // GraphQL ID 是字符串，暂时设为 0
-			User:      User{Login: node.Author.Login, HTMLURL: node.Author.URL},
-			CreatedAt: node.CreatedAt,
-			Body:      node.Body,
-			Reactions: reactions,
-			Deleted:   false,
+	// 代码行级 Review 评论：先按 in_reply_to_id 组成会话，再将会话归入发起它的 Review，
+	// 找不到所属 Review 时作为独立条目
+	reviewCommentsURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/comments", c.baseURL, owner, repo, number)
+	if reviewComments, err := getAll[restComment](ctx, c, reviewCommentsURL); err == nil {
+		for _, root := range buildReviewThreads(reviewComments) {
+			if idx, ok := reviewIndex[root.reviewID]; ok {
+				reviews[idx].Replies = append(reviews[idx].Replies, root.comment)
+				continue
+			}
+			thread = append(thread, root.comment)
 		}
 	}
 
-	return &Discussion{
-		Title:     d.Title,
-		URL:       d.URL,
-		User:      User{Login: d.Author.Login, HTMLURL: d.Author.URL},
-		CreatedAt: d.CreatedAt,
-		State:     state,
-		Body:      d.Body,
-		Comments:  comments,
-	}, nil
+	// 跳过既无正文也无行级评论的空 Review
+	for _, review := range reviews {
+		if review.Body == "" && len(review.Replies) == 0 && review.ReviewState == ReviewStateCommented {
+			continue
+		}
+		sortByCreatedAt(review.Replies)
+		thread = append(thread, review)
+	}
+
+	sortByCreatedAt(thread)
+
+	return thread
 }
 
-// get 发送 GET 请求
-func (c *Client) get(url string, v interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
+// reviewThreadRoot 会话首条评论及其所属 Review
+type reviewThreadRoot struct {
+	comment  Comment
+	reviewID int64
+}
 
-	// 添加认证头
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+// buildReviewThreads 将行级 Review 评论按 in_reply_to_id 组成会话
+// 返回每个会话的首条评论，回复按时间顺序放在首条评论的 Replies 中
+func buildReviewThreads(reviewComments []restComment) []reviewThreadRoot {
+	roots := []reviewThreadRoot{}
+	rootIndex := make(map[int64]int)
+	for _, cData := range reviewComments {
+		if cData.InReplyToID == 0 {
+			rootIndex[cData.ID] = len(roots)
+			roots = append(roots, reviewThreadRoot{
+				comment:  cData.toComment(CommentKindReviewComment),
+				reviewID: cData.PullRequestReviewID,
+			})
+		}
 	}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("%w: %v", ErrNetwork, err)
+	for _, cData := range reviewComments {
+		if cData.InReplyToID == 0 {
+			continue
+		}
+		idx, ok := rootIndex[cData.InReplyToID]
+		if !ok {
+			// 首条评论不可见时，回复自成一个会话
+			roots = append(roots, reviewThreadRoot{
+				comment:  cData.toComment(CommentKindReviewComment),
+				reviewID: cData.PullRequestReviewID,
+			})
+			continue
+		}
+		roots[idx].comment.Replies = append(roots[idx].comment.Replies, cData.toComment(CommentKindReviewComment))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return ErrResourceNotFound
+	for i := range roots {
+		sortByCreatedAt(roots[i].comment.Replies)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	return roots
+}
 
-	body, err := io.ReadAll(resp.Body)
+// sortByCreatedAt 按创建时间升序排列评论（时间相同时保持原有顺序）
+func sortByCreatedAt(comments []Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+}
+
+// get 发送 GET 请求
+func (c *Client) get(ctx context.Context, url string, v interface{}) error {
+	body, _, err := c.send(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return err
 	}
 
 	if err := json.Unmarshal(body, v); err != nil {
@@ -356,80 +521,363 @@ func (c *Client) get(url string, v interface{}) error {
 	return nil
 }
 
-// postGraphQL 发送 GraphQL POST 请求
-func (c *Client) postGraphQL(url string, query string, v interface{}) error {
-	payload := map[string]string{
-		"query": query,
+// send 发送 HTTP 请求，返回响应体和响应头
+// payload 非 nil 时作为 JSON 请求体发送（仅用于 GraphQL 查询，因此所有请求都可以安全重试）
+//   - 触发限流时，若等待时间在 rateLimitWait 之内则休眠后重试，否则返回 RateLimitError
+//   - 网络错误和 5xx 响应按重试策略退避后重试
+func (c *Client) send(ctx context.Context, method, url string, payload []byte) ([]byte, http.Header, error) {
+	start := c.now()
+	rateLimitRetries := 0
+
+	for attempt := 1; ; attempt++ {
+		body, header, err := c.sendOnce(ctx, method, url, payload)
+		if err == nil {
+			return body, header, nil
+		}
+
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			wait := rateLimitErr.Wait(c.now())
+			if c.rateLimitWait <= 0 || wait > c.rateLimitWait || rateLimitRetries >= maxRateLimitRetries {
+				return nil, nil, err
+			}
+			rateLimitRetries++
+			attempt-- // 限流等待不计入重试次数
+			c.logf("%s %s: %v; waiting %s", method, url, err, wait)
+			if err := c.sleep(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if !isRetryable(err) || attempt >= c.retry.MaxAttempts {
+			return nil, nil, err
+		}
+
+		delay := c.retry.backoff(attempt, c.random())
+		if c.retry.MaxElapsed > 0 && c.now().Sub(start)+delay > c.retry.MaxElapsed {
+			return nil, nil, err
+		}
+		c.logf("%s %s: attempt %d/%d failed: %v; retrying in %s", method, url, attempt, c.retry.MaxAttempts, err, delay)
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回 ctx 的错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
-	bodyBytes, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+// logf 输出详细日志（未设置 logger 时忽略）
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
 	}
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+// sendOnce 发送一次 HTTP 请求
+// 设置了缓存时，GET 请求优先使用 TTL 内的缓存，否则发送条件请求并在 304 时使用缓存
+func (c *Client) sendOnce(ctx context.Context, method, url string, payload []byte) ([]byte, http.Header, error) {
+	var cached *cacheEntry
+	if c.cache != nil && method == http.MethodGet {
+		cached = c.cache.load(url, c.token)
+		if cached != nil && c.cache.fresh(cached) {
+			c.logf("%s %s: cache hit", method, url)
+			return cached.Body, cached.Header, nil
+		}
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, nil, fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// 添加认证头
 	if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
+	if cached != nil {
+		cached.setConditionalHeaders(req)
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrNetwork, err)
+		// 主动取消不属于网络故障，直接返回 ctx 的错误（不会被重试）
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, fmt.Errorf("%w: %v", ErrNetwork, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.logf("%s %s: not modified, using cache", method, url)
+		if err := c.cache.store(url, c.token, cached.Header, cached.Body); err != nil {
+			c.logf("%v", err)
+		}
+		return cached.Body, cached.Header, nil
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
-		return ErrResourceNotFound
+		return nil, nil, ErrResourceNotFound
+	}
+
+	if rateLimitErr := parseRateLimit(resp); rateLimitErr != nil {
+		return nil, nil, rateLimitErr
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return nil, nil, fmt.Errorf("read response: %w", err)
 	}
 
-	if err := json.Unmarshal(body, v); err != nil {
-		return fmt.Errorf("parse response: %w", err)
+	// GraphQL 限流以 200 响应返回，交给 send 按限流等待后重试
+	if payload != nil {
+		if rateLimitErr := parseGraphQLRateLimit(resp, body); rateLimitErr != nil {
+			return nil, nil, rateLimitErr
+		}
 	}
 
-	return nil
+	if c.cache != nil && method == http.MethodGet {
+		if err := c.cache.store(url, c.token, resp.Header, body); err != nil {
+			c.logf("%v", err)
+		}
+	}
+
+	return body, resp.Header, nil
+}
+
+// restUser REST API 返回的用户信息
+type restUser struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+	Type    string `json:"type"` // "User", "Bot", "Organization", "Mannequin"
 }
 
-// buildReactions 构建 reactions 列表
-func buildReactions(r struct {
+// toUser 转换为通用用户：缺失或名为 ghost 的账号视为已删除，机器人去掉 "[bot]" 后缀
+func (u restUser) toUser() User {
+	if u.Login == "" || u.Login == ghostLogin {
+		return ghostUser()
+	}
+
+	user := User{Login: u.Login, HTMLURL: u.HTMLURL, Kind: UserKindUser}
+	switch {
+	case u.Type == "Bot" || strings.HasSuffix(u.Login, "[bot]"):
+		user.Login = strings.TrimSuffix(u.Login, "[bot]")
+		user.Kind = UserKindBot
+	case u.Type == "Mannequin":
+		user.Kind = UserKindMannequin
+	}
+	return user
+}
+
+// restReactions REST API 返回的 reactions 汇总
+type restReactions struct {
 	TotalCount int `json:"total_count"`
-	PlusOne    int `json:"plus_one"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
 	Heart      int `json:"heart"`
-}) []Reaction {
-	reactions := []Reaction{}
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
 
-	if r.PlusOne > 0 {
-		reactions = append(reactions, Reaction{Content: "+1", Count: r.PlusOne})
+// counts 以 reaction 类型为键返回各类数量
+func (r restReactions) counts() map[string]int {
+	return map[string]int{
+		"+1":       r.PlusOne,
+		"-1":       r.MinusOne,
+		"laugh":    r.Laugh,
+		"hooray":   r.Hooray,
+		"confused": r.Confused,
+		"heart":    r.Heart,
+		"rocket":   r.Rocket,
+		"eyes":     r.Eyes,
 	}
-	if r.Heart > 0 {
-		reactions = append(reactions, Reaction{Content: "heart", Count: r.Heart})
+}
+
+// restIssueMetadata Issue/PR 接口共有的分类与状态字段
+type restIssueMetadata struct {
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []restUser `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	ClosedBy    *restUser  `json:"closed_by"`
+	StateReason string     `json:"state_reason"`
+	Locked      bool       `json:"locked"`
+	Comments    int        `json:"comments"`
+}
+
+// toMetadata 转换为通用的 Metadata
+func (m restIssueMetadata) toMetadata() Metadata {
+	metadata := Metadata{
+		ClosedAt:     m.ClosedAt,
+		StateReason:  m.StateReason,
+		Locked:       m.Locked,
+		CommentCount: m.Comments,
+	}
+	for _, label := range m.Labels {
+		metadata.Labels = append(metadata.Labels, label.Name)
+	}
+	for _, assignee := range m.Assignees {
+		metadata.Assignees = append(metadata.Assignees, assignee.toUser())
+	}
+	if m.Milestone != nil {
+		metadata.Milestone = m.Milestone.Title
+	}
+	if m.ClosedBy != nil {
+		metadata.ClosedBy = m.ClosedBy.toUser()
+	}
+	return metadata
+}
+
+// restComment REST API 返回的评论（Issue 评论和 PR Review 评论共用）
+type restComment struct {
+	ID                int64             `json:"id"`
+	NodeID            string            `json:"node_id"`
+	User              restUser          `json:"user"`
+	AuthorAssociation AuthorAssociation `json:"author_association"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	Body              string            `json:"body"`
+	Reactions         restReactions     `json:"reactions"`
+
+	// 以下字段仅 Review 评论有
+	PullRequestReviewID int64  `json:"pull_request_review_id"`
+	InReplyToID         int64  `json:"in_reply_to_id"`
+	Path                string `json:"path"`
+	Line                *int   `json:"line"`
+	OriginalLine        *int   `json:"original_line"`
+	DiffHunk            string `json:"diff_hunk"`
+}
+
+// restReview REST API 返回的 PR Review
+type restReview struct {
+	ID                int64             `json:"id"`
+	NodeID            string            `json:"node_id"`
+	User              restUser          `json:"user"`
+	AuthorAssociation AuthorAssociation `json:"author_association"`
+	Body              string            `json:"body"`
+	State             string            `json:"state"`
+	SubmittedAt       *time.Time        `json:"submitted_at"`
+}
+
+// restFile REST API 返回的 PR 变更文件
+type restFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
+}
+
+// restCommit REST API 返回的 PR 提交
+type restCommit struct {
+	SHA     string    `json:"sha"`
+	HTMLURL string    `json:"html_url"`
+	Author  *restUser `json:"author"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// toComment 转换为通用评论
+func (rc restComment) toComment(kind CommentKind) Comment {
+	// 代码已变更的评论 line 为 null，退回到原始行号
+	line := 0
+	if rc.Line != nil {
+		line = *rc.Line
+	} else if rc.OriginalLine != nil {
+		line = *rc.OriginalLine
+	}
+
+	comment := Comment{
+		ID:                rc.ID,
+		NodeID:            rc.NodeID,
+		Kind:              kind,
+		User:              rc.User.toUser(),
+		AuthorAssociation: rc.AuthorAssociation,
+		CreatedAt:         rc.CreatedAt,
+		Body:              rc.Body,
+		Reactions:         buildReactions(rc.Reactions),
+		Deleted:           false,
+		Path:              rc.Path,
+		Line:              line,
+		DiffHunk:          rc.DiffHunk,
+		InReplyToID:       rc.InReplyToID,
+	}
+
+	// REST 不返回编辑者，无法使用 GraphQL 时以 updated_at 判断是否编辑过
+	if rc.UpdatedAt.After(rc.CreatedAt) {
+		updatedAt := rc.UpdatedAt
+		comment.EditedAt = &updatedAt
+	}
+	return comment
+}
+
+// buildReactions 构建 reactions 列表（按 GitHub 显示顺序，省略数量为 0 的类型）
+func buildReactions(r restReactions) []Reaction {
+	reactions := []Reaction{}
+
+	counts := r.counts()
+	for _, content := range ReactionContents {
+		if counts[content] > 0 {
+			reactions = append(reactions, Reaction{Content: content, Count: counts[content]})
+		}
 	}
 
 	return reactions
 }
 
+// graphQLReactionContents GraphQL ReactionContent 枚举到 REST reaction 类型的映射
+var graphQLReactionContents = map[string]string{
+	"THUMBS_UP":   "+1",
+	"THUMBS_DOWN": "-1",
+	"LAUGH":       "laugh",
+	"HOORAY":      "hooray",
+	"CONFUSED":    "confused",
+	"HEART":       "heart",
+	"ROCKET":      "rocket",
+	"EYES":        "eyes",
+}
+
 // convertReactionContent 转换 GraphQL reaction content
 func convertReactionContent(content string) string {
-	// GraphQL 返回的是大写（如 THUMBS_UP），转换为小写
-	switch content {
-	case "THUMBS_UP":
-		return "+1"
-	case "HEART":
-		return "heart"
-	default:
-		return content
+	// GraphQL 返回的是大写枚举（如 THUMBS_UP），转换为 REST 使用的名称
+	if converted, ok := graphQLReactionContents[content]; ok {
+		return converted
 	}
+	return content
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -105,7 +106,7 @@ func TestFetchIssue_WithComments(t *testing.T) {
 					"body":       "First comment",
 					"reactions": map[string]interface{}{
 						"total_count": 5,
-						"plus_one":    3,
+						"+1":          3,
 						"heart":       2,
 					},
 				},
@@ -119,7 +120,7 @@ func TestFetchIssue_WithComments(t *testing.T) {
 					"body":       "Second comment",
 					"reactions": map[string]interface{}{
 						"total_count": 1,
-						"plus_one":    1,
+						"+1":          1,
 					},
 				},
 			}
@@ -280,6 +281,445 @@ func TestFetchPullRequest_Merged(t *testing.T) {
 	}
 }
 
+// TestFetchPullRequest_Thread 测试 PR 对话评论、Review 评论和 Review 总结合并为时间线
+func TestFetchPullRequest_Thread(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "Thread PR",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+			})
+		case "/repos/test-owner/test-repo/issues/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "body": "conversation late", "created_at": "2024-01-05T00:00:00Z"},
+				{"id": 2, "body": "conversation early", "created_at": "2024-01-02T00:00:00Z"},
+			})
+		case "/repos/test-owner/test-repo/pulls/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 3, "body": "inline note", "created_at": "2024-01-03T00:00:00Z"},
+			})
+		case "/repos/test-owner/test-repo/pulls/1/reviews":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 4, "body": "looks good overall", "state": "APPROVED", "submitted_at": "2024-01-04T00:00:00Z"},
+				{"id": 5, "body": "", "state": "COMMENTED", "submitted_at": "2024-01-03T00:00:00Z"},
+				{"id": 6, "body": "draft", "state": "PENDING", "submitted_at": nil},
+			})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	want := []struct {
+		body string
+		kind CommentKind
+	}{
+		{"conversation early", CommentKindIssue},
+		{"inline note", CommentKindReviewComment},
+		{"looks good overall", CommentKindReview},
+		{"conversation late", CommentKindIssue},
+	}
+
+	if len(pr.Comments) != len(want) {
+		t.Fatalf("expected %d thread entries, got %d", len(want), len(pr.Comments))
+	}
+
+	for i, w := range want {
+		if pr.Comments[i].Body != w.body {
+			t.Errorf("entry %d: expected body %q, got %q", i, w.body, pr.Comments[i].Body)
+		}
+		if pr.Comments[i].Kind != w.kind {
+			t.Errorf("entry %d: expected kind %q, got %q", i, w.kind, pr.Comments[i].Kind)
+		}
+	}
+}
+
+// TestFetchPullRequest_Reviews 测试 Review 的状态及其行级评论的归属
+func TestFetchPullRequest_Reviews(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "Reviewed PR",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+			})
+		case "/repos/test-owner/test-repo/issues/1/comments":
+			json.NewEncoder(w).Encode([]interface{}{})
+		case "/repos/test-owner/test-repo/pulls/1/reviews":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 10, "body": "", "state": "CHANGES_REQUESTED", "submitted_at": "2024-01-02T00:00:00Z",
+					"user": map[string]interface{}{"login": "reviewer"}},
+				{"id": 11, "body": "", "state": "APPROVED", "submitted_at": "2024-01-03T00:00:00Z",
+					"user": map[string]interface{}{"login": "lead"}},
+			})
+		case "/repos/test-owner/test-repo/pulls/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 21, "body": "second note", "created_at": "2024-01-02T00:00:00Z", "pull_request_review_id": 10},
+				{"id": 20, "body": "first note", "created_at": "2024-01-01T12:00:00Z", "pull_request_review_id": 10},
+			})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	if len(pr.Comments) != 2 {
+		t.Fatalf("expected 2 reviews in thread, got %d", len(pr.Comments))
+	}
+
+	changes := pr.Comments[0]
+	if changes.Kind != CommentKindReview || changes.ReviewState != ReviewStateChangesRequested {
+		t.Errorf("expected CHANGES_REQUESTED review first, got %q %q", changes.Kind, changes.ReviewState)
+	}
+	if len(changes.Replies) != 2 {
+		t.Fatalf("expected 2 inline comments nested in review, got %d", len(changes.Replies))
+	}
+	if changes.Replies[0].Body != "first note" {
+		t.Errorf("expected nested comments sorted by time, got %q first", changes.Replies[0].Body)
+	}
+
+	if pr.Comments[1].ReviewState != ReviewStateApproved {
+		t.Errorf("expected APPROVED review second, got %q", pr.Comments[1].ReviewState)
+	}
+}
+
+// TestBuildReviewThreads 测试行级 Review 评论按 in_reply_to_id 组成会话
+func TestBuildReviewThreads(t *testing.T) {
+	line := 12
+	originalLine := 30
+	comments := []restComment{
+		{ID: 1, Body: "root A", Path: "a.go", Line: &line, DiffHunk: "@@ a", PullRequestReviewID: 100,
+			CreatedAt: parseTime("2024-01-01T00:00:00Z")},
+		{ID: 2, Body: "root B (outdated)", Path: "b.go", OriginalLine: &originalLine, PullRequestReviewID: 100,
+			CreatedAt: parseTime("2024-01-01T00:01:00Z")},
+		{ID: 4, Body: "reply A2", Path: "a.go", InReplyToID: 1, PullRequestReviewID: 102,
+			CreatedAt: parseTime("2024-01-03T00:00:00Z")},
+		{ID: 3, Body: "reply A1", Path: "a.go", InReplyToID: 1, PullRequestReviewID: 101,
+			CreatedAt: parseTime("2024-01-02T00:00:00Z")},
+		{ID: 5, Body: "orphan reply", Path: "c.go", InReplyToID: 99, PullRequestReviewID: 103,
+			CreatedAt: parseTime("2024-01-04T00:00:00Z")},
+	}
+
+	roots := buildReviewThreads(comments)
+
+	if len(roots) != 3 {
+		t.Fatalf("expected 3 threads, got %d", len(roots))
+	}
+
+	rootA := roots[0].comment
+	if rootA.Path != "a.go" || rootA.Line != 12 || rootA.DiffHunk != "@@ a" {
+		t.Errorf("unexpected thread location: %q line %d hunk %q", rootA.Path, rootA.Line, rootA.DiffHunk)
+	}
+	if len(rootA.Replies) != 2 || rootA.Replies[0].Body != "reply A1" || rootA.Replies[1].Body != "reply A2" {
+		t.Errorf("expected replies sorted by time under root A, got %+v", rootA.Replies)
+	}
+	if roots[0].reviewID != 100 {
+		t.Errorf("thread should belong to the review of its root comment, got %d", roots[0].reviewID)
+	}
+
+	if roots[1].comment.Line != 30 {
+		t.Errorf("outdated comment should fall back to original_line, got %d", roots[1].comment.Line)
+	}
+
+	if roots[2].comment.Body != "orphan reply" || roots[2].reviewID != 103 {
+		t.Errorf("orphan reply should start its own thread, got %+v", roots[2])
+	}
+}
+
+// TestFetchPullRequest_ReviewThreadStates 测试通过 GraphQL 获取代码会话的解决/过时状态
+func TestFetchPullRequest_ReviewThreadStates(t *testing.T) {
+	var graphQLRequests int
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "PR with Threads",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+			})
+		case "/repos/test-owner/test-repo/pulls/1/reviews":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 10, "state": "COMMENTED", "submitted_at": "2024-01-02T00:00:00Z"},
+			})
+		case "/repos/test-owner/test-repo/pulls/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 20, "body": "resolved", "path": "a.go", "pull_request_review_id": 10, "created_at": "2024-01-02T00:00:00Z"},
+				{"id": 21, "body": "open", "path": "b.go", "pull_request_review_id": 10, "created_at": "2024-01-02T00:00:00Z"},
+				{"id": 22, "body": "reply", "path": "a.go", "in_reply_to_id": 20, "created_at": "2024-01-03T00:00:00Z"},
+			})
+		case "/graphql":
+			graphQLRequests++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"pullRequest": map[string]interface{}{
+							"reviewThreads": map[string]interface{}{
+								"pageInfo": map[string]interface{}{"hasNextPage": false},
+								"nodes": []map[string]interface{}{
+									{
+										"isResolved": true,
+										"isOutdated": true,
+										"resolvedBy": map[string]interface{}{"login": "maintainer"},
+										"comments": map[string]interface{}{
+											"nodes": []map[string]interface{}{{"databaseId": 20}},
+										},
+									},
+									{
+										"isResolved": false,
+										"isOutdated": false,
+										"resolvedBy": nil,
+										"comments": map[string]interface{}{
+											"nodes": []map[string]interface{}{{"databaseId": 21}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	if graphQLRequests != 1 {
+		t.Errorf("expected 1 GraphQL request, got %d", graphQLRequests)
+	}
+
+	if len(pr.Comments) != 1 || len(pr.Comments[0].Replies) != 2 {
+		t.Fatalf("expected 1 review with 2 threads, got %+v", pr.Comments)
+	}
+
+	resolved := pr.Comments[0].Replies[0]
+	if !resolved.Resolved || !resolved.Outdated || resolved.ResolvedBy.Login != "maintainer" {
+		t.Errorf("expected first thread resolved and outdated by maintainer, got %+v", resolved)
+	}
+
+	open := pr.Comments[0].Replies[1]
+	if open.Resolved || open.Outdated {
+		t.Errorf("expected second thread to stay open, got %+v", open)
+	}
+}
+
+// TestFetchPullRequest_Files 测试获取 PR 变更文件
+func TestFetchPullRequest_Files(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "PR with Files",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+			})
+		case "/repos/test-owner/test-repo/pulls/1/files":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"filename": "main.go", "status": "modified", "additions": 3, "deletions": 1, "patch": "@@ -1 +1 @@"},
+				{"filename": "logo.png", "status": "added", "additions": 0, "deletions": 0},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	if len(pr.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(pr.Files))
+	}
+
+	want := ChangedFile{Filename: "main.go", Status: "modified", Additions: 3, Deletions: 1, Patch: "@@ -1 +1 @@"}
+	if pr.Files[0] != want {
+		t.Errorf("expected %+v, got %+v", want, pr.Files[0])
+	}
+
+	if pr.Files[1].Patch != "" {
+		t.Errorf("expected empty patch for binary file, got %q", pr.Files[1].Patch)
+	}
+}
+
+// TestFetchPullRequest_MergeMetadata 测试获取 PR 分支、合并信息和提交列表
+func TestFetchPullRequest_MergeMetadata(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":            "Merged PR",
+				"created_at":       "2024-01-01T00:00:00Z",
+				"state":            "closed",
+				"merged":           true,
+				"draft":            false,
+				"merge_commit_sha": "mergesha",
+				"merged_at":        "2024-01-03T00:00:00Z",
+				"merged_by":        map[string]interface{}{"login": "maintainer", "html_url": "https://github.com/maintainer"},
+				"base":             map[string]interface{}{"ref": "main"},
+				"head":             map[string]interface{}{"ref": "feature", "sha": "headsha"},
+			})
+		case "/repos/test-owner/test-repo/pulls/1/commits":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{
+					"sha":      "c1",
+					"html_url": "https://github.com/test-owner/test-repo/commit/c1",
+					"author":   map[string]interface{}{"login": "dev"},
+					"commit": map[string]interface{}{
+						"message": "First commit",
+						"author":  map[string]interface{}{"name": "Dev", "date": "2024-01-02T00:00:00Z"},
+					},
+				},
+				{
+					"sha":    "c2",
+					"author": nil,
+					"commit": map[string]interface{}{
+						"message": "Second commit",
+						"author":  map[string]interface{}{"name": "Someone", "date": "2024-01-02T01:00:00Z"},
+					},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	if pr.BaseRef != "main" || pr.HeadRef != "feature" || pr.HeadSHA != "headsha" {
+		t.Errorf("unexpected refs: base %q head %q sha %q", pr.BaseRef, pr.HeadRef, pr.HeadSHA)
+	}
+
+	if pr.MergeCommitSHA != "mergesha" || pr.MergedBy.Login != "maintainer" {
+		t.Errorf("unexpected merge info: sha %q by %q", pr.MergeCommitSHA, pr.MergedBy.Login)
+	}
+
+	if pr.MergedAt == nil || !pr.MergedAt.Equal(parseTime("2024-01-03T00:00:00Z")) {
+		t.Errorf("unexpected merged_at: %v", pr.MergedAt)
+	}
+
+	if len(pr.Commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(pr.Commits))
+	}
+
+	if pr.Commits[0].Author.Login != "dev" || pr.Commits[0].Message != "First commit" {
+		t.Errorf("unexpected first commit: %+v", pr.Commits[0])
+	}
+
+	if pr.Commits[1].Author.Login != "" || pr.Commits[1].AuthorName != "Someone" {
+		t.Errorf("commit without linked account should keep git author name, got %+v", pr.Commits[1])
+	}
+}
+
+// TestFetchPullRequest_Checks 测试获取 HEAD 提交上的 check runs 和 commit statuses
+func TestFetchPullRequest_Checks(t *testing.T) {
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "PR with Checks",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+				"head":       map[string]interface{}{"ref": "feature", "sha": "headsha"},
+			})
+		case "/repos/test-owner/test-repo/commits/headsha/check-runs":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+mockServer.URL+r.URL.Path+`?page=2>; rel="next"`)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"total_count": 2,
+					"check_runs": []map[string]interface{}{
+						{"name": "build", "status": "completed", "conclusion": "success",
+							"started_at": "2024-01-02T00:00:00Z", "completed_at": "2024-01-02T00:03:00Z",
+							"details_url": "https://ci.example.com/1"},
+					},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 2,
+				"check_runs": []map[string]interface{}{
+					{"name": "lint", "status": "in_progress", "conclusion": nil},
+				},
+			})
+		case "/repos/test-owner/test-repo/commits/headsha/status":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"state": "failure",
+				"statuses": []map[string]interface{}{
+					{"context": "ci/jenkins", "state": "failure", "target_url": "https://jenkins.example.com/1"},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	pr, err := client.FetchPullRequest("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+
+	if len(pr.Checks) != 3 {
+		t.Fatalf("expected 3 checks, got %d", len(pr.Checks))
+	}
+
+	build := pr.Checks[0]
+	if build.Name != "build" || build.Kind != CheckKindCheckRun || build.Conclusion != "success" {
+		t.Errorf("unexpected first check: %+v", build)
+	}
+	if build.StartedAt == nil || build.CompletedAt == nil || build.CompletedAt.Sub(*build.StartedAt).Minutes() != 3 {
+		t.Errorf("expected check run timing to be kept, got %v - %v", build.StartedAt, build.CompletedAt)
+	}
+
+	if pr.Checks[1].Name != "lint" || pr.Checks[1].Status != "in_progress" {
+		t.Errorf("expected second page check run, got %+v", pr.Checks[1])
+	}
+
+	jenkins := pr.Checks[2]
+	if jenkins.Kind != CheckKindStatus || jenkins.Conclusion != "failure" || jenkins.DetailsURL != "https://jenkins.example.com/1" {
+		t.Errorf("unexpected commit status: %+v", jenkins)
+	}
+}
+
 // TestFetchDiscussion_Success 测试成功获取 Discussion
 func TestFetchDiscussion_Success(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -356,6 +796,90 @@ func TestFetchDiscussion_Success(t *testing.T) {
 	}
 }
 
+// TestFetchDiscussion_RepliesAndAnswer 测试 Discussion 嵌套回复、采纳答案、分类和点赞
+func TestFetchDiscussion_RepliesAndAnswer(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockResponse := map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"discussion": map[string]interface{}{
+						"title":          "Q&A Discussion",
+						"createdAt":      "2024-01-01T00:00:00Z",
+						"body":           "Question",
+						"upvoteCount":    7,
+						"category":       map[string]interface{}{"name": "Q&A"},
+						"answerChosenAt": "2024-01-05T00:00:00Z",
+						"answerChosenBy": map[string]interface{}{"login": "asker"},
+						"comments": map[string]interface{}{
+							"nodes": []map[string]interface{}{
+								{
+									"id":          "c2",
+									"author":      map[string]interface{}{"login": "late"},
+									"createdAt":   "2024-01-04T00:00:00Z",
+									"body":        "Later comment",
+									"upvoteCount": 0,
+								},
+								{
+									"id":          "c1",
+									"author":      map[string]interface{}{"login": "helper"},
+									"createdAt":   "2024-01-02T00:00:00Z",
+									"body":        "The answer",
+									"isAnswer":    true,
+									"upvoteCount": 3,
+									"replies": map[string]interface{}{
+										"nodes": []map[string]interface{}{
+											{"id": "r2", "author": map[string]interface{}{"login": "asker"},
+												"createdAt": "2024-01-03T12:00:00Z", "body": "Second reply"},
+											{"id": "r1", "author": map[string]interface{}{"login": "asker"},
+												"createdAt": "2024-01-03T00:00:00Z", "body": "First reply"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	discussion, err := client.FetchDiscussion("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+
+	if discussion.Category != "Q&A" || discussion.UpvoteCount != 7 {
+		t.Errorf("unexpected category %q / upvotes %d", discussion.Category, discussion.UpvoteCount)
+	}
+
+	if discussion.AnswerChosenBy.Login != "asker" || discussion.AnswerChosenAt == nil {
+		t.Errorf("expected answer chosen by asker, got %+v at %v", discussion.AnswerChosenBy, discussion.AnswerChosenAt)
+	}
+
+	if len(discussion.Comments) != 2 {
+		t.Fatalf("expected 2 top-level comments, got %d", len(discussion.Comments))
+	}
+
+	answer := discussion.Comments[0]
+	if answer.Body != "The answer" || !answer.IsAnswer || answer.UpvoteCount != 3 {
+		t.Errorf("expected answer first (sorted by time), got %+v", answer)
+	}
+
+	if len(answer.Replies) != 2 || answer.Replies[0].Body != "First reply" || answer.Replies[1].Body != "Second reply" {
+		t.Errorf("expected 2 replies sorted by time, got %+v", answer.Replies)
+	}
+
+	if discussion.Comments[1].IsAnswer || len(discussion.Comments[1].Replies) != 0 {
+		t.Errorf("unexpected second comment: %+v", discussion.Comments[1])
+	}
+}
+
 // TestNewClient 测试创建 Client
 func TestNewClient(t *testing.T) {
 	tests := []struct {
@@ -393,3 +917,749 @@ func parseTime(timeStr string) time.Time {
 	}
 	return t
 }
+
+// TestBuildReactions 测试 REST reactions 汇总包含全部 8 种类型，并按 GitHub 顺序排列
+func TestBuildReactions(t *testing.T) {
+	var r restReactions
+	payload := `{"total_count":36,"+1":1,"-1":2,"laugh":3,"hooray":4,"confused":5,"heart":6,"rocket":7,"eyes":8}`
+	if err := json.Unmarshal([]byte(payload), &r); err != nil {
+		t.Fatalf("unmarshal reactions: %v", err)
+	}
+
+	reactions := buildReactions(r)
+	if len(reactions) != len(ReactionContents) {
+		t.Fatalf("expected %d reaction types, got %+v", len(ReactionContents), reactions)
+	}
+	for i, content := range ReactionContents {
+		if reactions[i].Content != content || reactions[i].Count != i+1 {
+			t.Errorf("reaction %d: expected %s x%d, got %+v", i, content, i+1, reactions[i])
+		}
+	}
+
+	if got := buildReactions(restReactions{Eyes: 1}); len(got) != 1 || got[0].Content != "eyes" {
+		t.Errorf("expected zero counts to be omitted, got %+v", got)
+	}
+}
+
+// TestConvertReactionContent 测试 GraphQL reaction 枚举的转换
+func TestConvertReactionContent(t *testing.T) {
+	tests := map[string]string{
+		"THUMBS_UP":   "+1",
+		"THUMBS_DOWN": "-1",
+		"LAUGH":       "laugh",
+		"HOORAY":      "hooray",
+		"CONFUSED":    "confused",
+		"HEART":       "heart",
+		"ROCKET":      "rocket",
+		"EYES":        "eyes",
+	}
+
+	for input, want := range tests {
+		if got := convertReactionContent(input); got != want {
+			t.Errorf("convertReactionContent(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestFetchBodyReactions 测试 Issue、PR 和 Discussion 正文的 reactions
+func TestFetchBodyReactions(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":     "Issue or PR",
+				"reactions": map[string]interface{}{"total_count": 5, "+1": 4, "rocket": 1},
+			})
+		case "/repos/owner/repo/pulls/1":
+			// PR 接口不包含 reactions
+			json.NewEncoder(w).Encode(map[string]interface{}{"title": "PR"})
+		case "/graphql":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"discussion": map[string]interface{}{
+							"id":    "D_1",
+							"title": "Discussion",
+							"reactionGroups": []map[string]interface{}{
+								{"content": "THUMBS_UP", "users": map[string]interface{}{"totalCount": 0}},
+								{"content": "EYES", "users": map[string]interface{}{"totalCount": 2}},
+								{"content": "THUMBS_DOWN", "users": map[string]interface{}{"totalCount": 1}},
+							},
+						},
+					},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	want := []Reaction{{Content: "+1", Count: 4}, {Content: "rocket", Count: 1}}
+
+	issue, err := client.FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	if len(issue.Reactions) != 2 || issue.Reactions[0] != want[0] || issue.Reactions[1] != want[1] {
+		t.Errorf("expected issue reactions %+v, got %+v", want, issue.Reactions)
+	}
+
+	pr, err := client.FetchPullRequest("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+	if len(pr.Reactions) != 2 || pr.Reactions[0] != want[0] || pr.Reactions[1] != want[1] {
+		t.Errorf("expected PR reactions from issue payload %+v, got %+v", want, pr.Reactions)
+	}
+
+	discussion, err := client.FetchDiscussion("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+	counts := map[string]int{}
+	for _, reaction := range discussion.Reactions {
+		counts[reaction.Content] = reaction.Count
+	}
+	if len(counts) != 2 || counts["eyes"] != 2 || counts["-1"] != 1 {
+		t.Errorf("expected discussion reactions eyes=2 -1=1, got %+v", discussion.Reactions)
+	}
+}
+
+// TestWithHost 测试按主机名设置 REST 和 GraphQL 地址
+func TestWithHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		wantBaseURL string
+		wantGraphQL string
+	}{
+		{
+			name:        "github.com",
+			host:        "github.com",
+			wantBaseURL: "https://api.github.com",
+			wantGraphQL: "https://api.github.com/graphql",
+		},
+		{
+			name:        "enterprise server",
+			host:        "GHE.example.com",
+			wantBaseURL: "https://ghe.example.com/api/v3",
+			wantGraphQL: "https://ghe.example.com/api/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("", WithHost(tt.host))
+			if client.baseURL != tt.wantBaseURL || client.graphqlURL != tt.wantGraphQL {
+				t.Errorf("got (%s, %s), want (%s, %s)", client.baseURL, client.graphqlURL, tt.wantBaseURL, tt.wantGraphQL)
+			}
+		})
+	}
+}
+
+// TestFetchIssue_TimelineEvents 测试获取时间线事件（忽略评论和不支持的事件）
+func TestFetchIssue_TimelineEvents(t *testing.T) {
+	timelineRequested := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"title": "Issue"})
+		case "/repos/owner/repo/issues/1/timeline":
+			timelineRequested = true
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"event": "labeled", "actor": map[string]interface{}{"login": "alice"},
+					"created_at": "2024-05-01T00:00:00Z", "label": map[string]interface{}{"name": "bug"}},
+				{"event": "commented", "actor": map[string]interface{}{"login": "bob"},
+					"created_at": "2024-05-02T00:00:00Z", "body": "a comment"},
+				{"event": "subscribed", "actor": map[string]interface{}{"login": "bob"},
+					"created_at": "2024-05-02T00:00:00Z"},
+				{"event": "renamed", "actor": map[string]interface{}{"login": "alice"},
+					"created_at": "2024-05-03T00:00:00Z", "rename": map[string]interface{}{"from": "Old", "to": "New"}},
+				{"event": "cross-referenced", "actor": map[string]interface{}{"login": "carol"},
+					"created_at": "2024-05-04T00:00:00Z", "source": map[string]interface{}{
+						"type":  "issue",
+						"issue": map[string]interface{}{"title": "Other", "number": 9, "html_url": "https://github.com/owner/repo/issues/9"},
+					}},
+				{"event": "closed", "actor": map[string]interface{}{"login": "alice"},
+					"created_at": "2024-05-05T00:00:00Z", "state_reason": "completed", "commit_id": "abc123"},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	issue, err := NewClient("", WithBaseURL(mockServer.URL)).FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	if timelineRequested || issue.Events != nil {
+		t.Errorf("expected timeline not to be fetched by default")
+	}
+
+	issue, err = NewClient("", WithBaseURL(mockServer.URL), WithTimelineEvents(true)).FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+
+	if len(issue.Events) != 4 {
+		t.Fatalf("expected 4 events, got %+v", issue.Events)
+	}
+
+	labeled := issue.Events[0]
+	if labeled.Type != EventLabeled || labeled.Actor.Login != "alice" || labeled.Label != "bug" {
+		t.Errorf("unexpected labeled event: %+v", labeled)
+	}
+
+	renamed := issue.Events[1]
+	if renamed.RenameFrom != "Old" || renamed.RenameTo != "New" {
+		t.Errorf("unexpected renamed event: %+v", renamed)
+	}
+
+	crossRef := issue.Events[2]
+	if crossRef.SourceNumber != 9 || crossRef.SourceTitle != "Other" || crossRef.SourceURL == "" {
+		t.Errorf("unexpected cross-referenced event: %+v", crossRef)
+	}
+
+	closed := issue.Events[3]
+	if closed.StateReason != "completed" || closed.CommitSHA != "abc123" {
+		t.Errorf("unexpected closed event: %+v", closed)
+	}
+}
+
+// TestFetchMetadata 测试 Issue、PR 和 Discussion 的标签、指派、里程碑和关闭信息
+func TestFetchMetadata(t *testing.T) {
+	issuePayload := map[string]interface{}{
+		"title":        "Issue",
+		"state":        "closed",
+		"labels":       []map[string]interface{}{{"name": "bug"}, {"name": "help wanted"}},
+		"assignees":    []map[string]interface{}{{"login": "alice", "html_url": "https://github.com/alice"}},
+		"milestone":    map[string]interface{}{"title": "v1.0"},
+		"closed_at":    "2024-05-05T00:00:00Z",
+		"closed_by":    map[string]interface{}{"login": "bob"},
+		"state_reason": "completed",
+		"locked":       true,
+		"comments":     12,
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			json.NewEncoder(w).Encode(issuePayload)
+		case "/repos/owner/repo/pulls/1":
+			// PR 接口没有 closed_by 和 state_reason
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":     "PR",
+				"state":     "closed",
+				"labels":    []map[string]interface{}{{"name": "bug"}, {"name": "help wanted"}},
+				"assignees": []map[string]interface{}{{"login": "alice"}},
+				"milestone": map[string]interface{}{"title": "v1.0"},
+				"closed_at": "2024-05-05T00:00:00Z",
+				"locked":    true,
+				"comments":  12,
+			})
+		case "/graphql":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"discussion": map[string]interface{}{
+							"title":       "Discussion",
+							"closedAt":    "2024-05-05T00:00:00Z",
+							"stateReason": "RESOLVED",
+							"locked":      true,
+							"labels": map[string]interface{}{
+								"nodes": []map[string]interface{}{{"name": "bug"}, {"name": "help wanted"}},
+							},
+							"comments": map[string]interface{}{"totalCount": 12},
+						},
+					},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	issue, err := client.FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	pr, err := client.FetchPullRequest("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchPullRequest failed: %v", err)
+	}
+	discussion, err := client.FetchDiscussion("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+
+	closedAt := parseTime("2024-05-05T00:00:00Z")
+	for name, meta := range map[string]Metadata{"issue": issue.Metadata, "pr": pr.Metadata, "discussion": discussion.Metadata} {
+		if len(meta.Labels) != 2 || meta.Labels[0] != "bug" || meta.Labels[1] != "help wanted" {
+			t.Errorf("%s: unexpected labels %v", name, meta.Labels)
+		}
+		if meta.ClosedAt == nil || !meta.ClosedAt.Equal(closedAt) {
+			t.Errorf("%s: unexpected closed_at %v", name, meta.ClosedAt)
+		}
+		if !meta.Locked || meta.CommentCount != 12 {
+			t.Errorf("%s: expected locked with 12 comments, got %+v", name, meta)
+		}
+	}
+
+	for name, meta := range map[string]Metadata{"issue": issue.Metadata, "pr": pr.Metadata} {
+		if len(meta.Assignees) != 1 || meta.Assignees[0].Login != "alice" || meta.Milestone != "v1.0" {
+			t.Errorf("%s: unexpected assignees/milestone %+v", name, meta)
+		}
+		if meta.ClosedBy.Login != "bob" || meta.StateReason != "completed" {
+			t.Errorf("%s: expected closed by bob (completed), got %+v / %q", name, meta.ClosedBy, meta.StateReason)
+		}
+	}
+
+	if discussion.StateReason != "resolved" {
+		t.Errorf("expected discussion state reason resolved, got %q", discussion.StateReason)
+	}
+}
+
+func TestFetchIssue_EditHistory(t *testing.T) {
+	tests := []struct {
+		name          string
+		editHistory   bool
+		wantRevisions int
+	}{
+		{name: "last edit only", editHistory: false, wantRevisions: 0},
+		{name: "with revisions", editHistory: true, wantRevisions: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []interface{}
+			var gotQuery string
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/repos/owner/repo/issues/1":
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"node_id": "I_1", "title": "Issue", "state": "open", "created_at": "2024-05-01T00:00:00Z",
+					})
+				case "/repos/owner/repo/issues/1/comments":
+					json.NewEncoder(w).Encode([]map[string]interface{}{
+						{"id": 10, "node_id": "IC_10", "user": map[string]interface{}{"login": "bob"},
+							"created_at": "2024-05-01T01:00:00Z", "updated_at": "2024-05-01T01:00:00Z", "body": "unchanged"},
+						{"id": 11, "node_id": "IC_11", "user": map[string]interface{}{"login": "carol"},
+							"created_at": "2024-05-01T02:00:00Z", "updated_at": "2024-05-01T03:00:00Z", "body": "edited"},
+					})
+				case "/graphql":
+					var payload struct {
+						Query     string                 `json:"query"`
+						Variables map[string]interface{} `json:"variables"`
+					}
+					json.NewDecoder(r.Body).Decode(&payload)
+					gotQuery = payload.Query
+					gotIDs, _ = payload.Variables["ids"].([]interface{})
+					body := map[string]interface{}{
+						"id":           "I_1",
+						"lastEditedAt": "2024-05-02T00:00:00Z",
+						"editor":       map[string]interface{}{"login": "alice", "url": "https://github.com/alice"},
+					}
+					if strings.Contains(payload.Query, "userContentEdits") {
+						body["userContentEdits"] = map[string]interface{}{
+							"nodes": []map[string]interface{}{
+								{"editedAt": "2024-05-02T00:00:00Z", "editor": map[string]interface{}{"login": "alice"}, "diff": "v2"},
+								{"editedAt": "2024-05-01T00:00:00Z", "editor": map[string]interface{}{"login": "alice"}, "diff": "v1"},
+							},
+						}
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"data": map[string]interface{}{
+							"nodes": []interface{}{
+								body,
+								map[string]interface{}{"id": "IC_10", "lastEditedAt": nil},
+								nil,
+							},
+						},
+					})
+				default:
+					json.NewEncoder(w).Encode([]interface{}{})
+				}
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("token", WithBaseURL(mockServer.URL), WithEditHistory(tt.editHistory))
+			issue, err := client.FetchIssue("owner", "repo", 1)
+			if err != nil {
+				t.Fatalf("FetchIssue failed: %v", err)
+			}
+
+			if len(gotIDs) != 3 || gotIDs[0] != "I_1" || gotIDs[2] != "IC_11" {
+				t.Errorf("expected node IDs of body and comments, got %v", gotIDs)
+			}
+			if strings.Contains(gotQuery, "userContentEdits") != tt.editHistory {
+				t.Errorf("userContentEdits requested = %v, want %v", !tt.editHistory, tt.editHistory)
+			}
+
+			if issue.EditedAt == nil || !issue.EditedAt.Equal(parseTime("2024-05-02T00:00:00Z")) || issue.Editor.Login != "alice" {
+				t.Errorf("unexpected body edit info: %+v", issue.EditHistory)
+			}
+			if len(issue.Revisions) != tt.wantRevisions {
+				t.Fatalf("expected %d revisions, got %d", tt.wantRevisions, len(issue.Revisions))
+			}
+			if tt.wantRevisions > 0 && (issue.Revisions[0].Body != "v1" || issue.Revisions[1].Body != "v2") {
+				t.Errorf("revisions should be sorted oldest first, got %+v", issue.Revisions)
+			}
+
+			// 未编辑的评论没有编辑时间；GraphQL 未返回的节点保留 REST updated_at
+			if issue.Comments[0].EditedAt != nil {
+				t.Errorf("unedited comment should have no edit time, got %v", issue.Comments[0].EditedAt)
+			}
+			if issue.Comments[1].EditedAt == nil || !issue.Comments[1].EditedAt.Equal(parseTime("2024-05-01T03:00:00Z")) {
+				t.Errorf("expected REST updated_at fallback, got %v", issue.Comments[1].EditedAt)
+			}
+		})
+	}
+}
+
+func TestFetch_MinimizedComments(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"node_id": "I_1", "title": "Issue", "state": "open"})
+		case "/repos/owner/repo/issues/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 10, "node_id": "IC_10", "user": map[string]interface{}{"login": "spammer"}, "body": "buy now"},
+				{"id": 11, "node_id": "IC_11", "user": map[string]interface{}{"login": "bob"}, "body": "useful"},
+			})
+		case "/graphql":
+			var payload struct {
+				Variables map[string]interface{} `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if _, ok := payload.Variables["ids"]; ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"nodes": []interface{}{
+							map[string]interface{}{"id": "I_1"},
+							map[string]interface{}{"id": "IC_10", "isMinimized": true, "minimizedReason": "SPAM"},
+							map[string]interface{}{"id": "IC_11", "isMinimized": false},
+						},
+					},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"repository": map[string]interface{}{
+						"discussion": map[string]interface{}{
+							"title": "Discussion",
+							"comments": map[string]interface{}{
+								"nodes": []map[string]interface{}{
+									{"id": "DC_1", "createdAt": "2024-05-01T00:00:00Z", "deletedAt": "2024-05-02T00:00:00Z",
+										"replies": map[string]interface{}{"nodes": []map[string]interface{}{
+											{"id": "DC_2", "createdAt": "2024-05-01T01:00:00Z", "body": "me too",
+												"isMinimized": true, "minimizedReason": "off-topic"},
+										}}},
+								},
+							},
+						},
+					},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("token", WithBaseURL(mockServer.URL))
+
+	issue, err := client.FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	if !issue.Comments[0].Minimized || issue.Comments[0].MinimizedReason != "spam" {
+		t.Errorf("expected first comment minimized as spam, got %+v", issue.Comments[0])
+	}
+	if issue.Comments[1].Minimized || issue.Comments[1].MinimizedReason != "" {
+		t.Errorf("expected second comment visible, got %+v", issue.Comments[1])
+	}
+
+	discussion, err := client.FetchDiscussion("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+	if len(discussion.Comments) != 1 || !discussion.Comments[0].Deleted || discussion.Comments[0].Minimized {
+		t.Fatalf("expected deleted top-level comment, got %+v", discussion.Comments)
+	}
+	reply := discussion.Comments[0].Replies[0]
+	if reply.Deleted || !reply.Minimized || reply.MinimizedReason != "off-topic" {
+		t.Errorf("expected minimized reply, got %+v", reply)
+	}
+}
+
+func TestToUser_Kinds(t *testing.T) {
+	ghost := User{Login: "ghost", Kind: UserKindGhost}
+
+	restTests := []struct {
+		name string
+		user restUser
+		want User
+	}{
+		{name: "user", user: restUser{Login: "alice", HTMLURL: "https://github.com/alice", Type: "User"},
+			want: User{Login: "alice", HTMLURL: "https://github.com/alice", Kind: UserKindUser}},
+		{name: "bot", user: restUser{Login: "dependabot[bot]", HTMLURL: "https://github.com/apps/dependabot", Type: "Bot"},
+			want: User{Login: "dependabot", HTMLURL: "https://github.com/apps/dependabot", Kind: UserKindBot}},
+		{name: "bot suffix without type", user: restUser{Login: "renovate[bot]"},
+			want: User{Login: "renovate", Kind: UserKindBot}},
+		{name: "mannequin", user: restUser{Login: "old-user", Type: "Mannequin"},
+			want: User{Login: "old-user", Kind: UserKindMannequin}},
+		{name: "ghost", user: restUser{Login: "ghost", HTMLURL: "https://ghe.example.com/ghost", Type: "User"}, want: ghost},
+		{name: "null user", user: restUser{}, want: ghost},
+	}
+	for _, tt := range restTests {
+		t.Run("rest "+tt.name, func(t *testing.T) {
+			if got := tt.user.toUser(); got != tt.want {
+				t.Errorf("toUser() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	graphQLTests := []struct {
+		name  string
+		actor *graphQLActor
+		want  User
+	}{
+		{name: "user", actor: &graphQLActor{Typename: "User", Login: "alice", URL: "https://github.com/alice"},
+			want: User{Login: "alice", HTMLURL: "https://github.com/alice", Kind: UserKindUser}},
+		{name: "bot", actor: &graphQLActor{Typename: "Bot", Login: "github-actions", URL: "https://github.com/apps/github-actions"},
+			want: User{Login: "github-actions", HTMLURL: "https://github.com/apps/github-actions", Kind: UserKindBot}},
+		{name: "mannequin", actor: &graphQLActor{Typename: "Mannequin", Login: "old-user"},
+			want: User{Login: "old-user", Kind: UserKindMannequin}},
+		{name: "null author", actor: nil, want: ghost},
+	}
+	for _, tt := range graphQLTests {
+		t.Run("graphql "+tt.name, func(t *testing.T) {
+			if got := tt.actor.toUser(); got != tt.want {
+				t.Errorf("toUser() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchDiscussion_NullAuthor(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"discussion": map[string]interface{}{
+						"title":  "Discussion",
+						"author": nil,
+						"comments": map[string]interface{}{
+							"nodes": []map[string]interface{}{
+								{"id": "DC_1", "author": nil, "body": "orphaned"},
+								{"id": "DC_2", "author": map[string]interface{}{"__typename": "Bot", "login": "stale", "url": "https://github.com/apps/stale"}},
+							},
+						},
+					},
+				},
+			},
+		})
+	}))
+	defer mockServer.Close()
+
+	discussion, err := NewClient("", WithBaseURL(mockServer.URL)).FetchDiscussion("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+	if discussion.User.Login != "ghost" || discussion.User.Kind != UserKindGhost {
+		t.Errorf("expected ghost author, got %+v", discussion.User)
+	}
+	if discussion.Comments[0].User.Kind != UserKindGhost || discussion.Comments[1].User.Kind != UserKindBot {
+		t.Errorf("unexpected comment authors: %+v, %+v", discussion.Comments[0].User, discussion.Comments[1].User)
+	}
+}
+
+func TestFetchIssue_AuthorAssociation(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title": "Issue", "user": map[string]interface{}{"login": "carol"}, "author_association": "NONE",
+			})
+		case "/repos/owner/repo/issues/1/comments":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "user": map[string]interface{}{"login": "alice"}, "author_association": "OWNER"},
+				{"id": 2, "user": map[string]interface{}{"login": "dave"}, "author_association": "CONTRIBUTOR"},
+			})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	issue, err := NewClient("", WithBaseURL(mockServer.URL)).FetchIssue("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	if issue.AuthorAssociation != AssociationNone {
+		t.Errorf("expected NONE, got %q", issue.AuthorAssociation)
+	}
+	want := []AuthorAssociation{AssociationOwner, AssociationContributor}
+	for i, comment := range issue.Comments {
+		if comment.AuthorAssociation != want[i] {
+			t.Errorf("comment %d: expected %q, got %q", i, want[i], comment.AuthorAssociation)
+		}
+	}
+	if !issue.Comments[0].AuthorAssociation.IsMaintainer() || issue.Comments[1].AuthorAssociation.IsMaintainer() {
+		t.Errorf("only OWNER should be a maintainer here")
+	}
+}
+
+func TestParseTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Task
+	}{
+		{name: "no tasks", body: "Plain body\n- regular item", want: []Task{}},
+		{
+			name: "mixed markers",
+			body: "Plan:\r\n- [ ] write docs\n* [x] add tests\n+ [X] release  \n1. [ ] numbered",
+			want: []Task{
+				{Text: "write docs"},
+				{Text: "add tests", Done: true},
+				{Text: "release", Done: true},
+				{Text: "numbered"},
+			},
+		},
+		{name: "nested", body: "- [x] parent\n  - [ ] child", want: []Task{{Text: "parent", Done: true}, {Text: "child"}}},
+		{
+			name: "code blocks ignored",
+			body: "```md\n- [ ] not a task\n```\n~~~~\n- [x] also not\n~~~\n~~~~\n- [ ] real task",
+			want: []Task{{Text: "real task"}},
+		},
+		{name: "empty checkbox text", body: "- [ ] \n- [] broken", want: []Task{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTasks(tt.body)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTasks() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("task %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFetchIssue_SubIssues(t *testing.T) {
+	tests := []struct {
+		name          string
+		summary       interface{}
+		wantRequested bool
+		wantSubIssues int
+	}{
+		{name: "with sub-issues", summary: map[string]interface{}{"total": 2, "completed": 1}, wantRequested: true, wantSubIssues: 2},
+		{name: "summary says none", summary: map[string]interface{}{"total": 0, "completed": 0}, wantRequested: false},
+		{name: "summary missing", summary: nil, wantRequested: true, wantSubIssues: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := false
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/repos/owner/repo/issues/5":
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"title": "Epic", "body": "- [x] design\n- [ ] rollout", "sub_issues_summary": tt.summary,
+						"parent_issue_url": "https://api.github.com/repos/owner/repo/issues/1",
+					})
+				case "/repos/owner/repo/issues/5/sub_issues":
+					requested = true
+					json.NewEncoder(w).Encode([]map[string]interface{}{
+						{"number": 6, "title": "Backend", "state": "closed", "state_reason": "completed", "html_url": "https://github.com/owner/repo/issues/6"},
+						{"number": 7, "title": "Frontend", "state": "open", "html_url": "https://github.com/owner/repo/issues/7"},
+					})
+				case "/repos/owner/repo/issues/5/parent":
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"number": 1, "title": "Roadmap", "state": "open", "html_url": "https://github.com/owner/repo/issues/1",
+					})
+				default:
+					json.NewEncoder(w).Encode([]interface{}{})
+				}
+			}))
+			defer mockServer.Close()
+
+			issue, err := NewClient("", WithBaseURL(mockServer.URL)).FetchIssue("owner", "repo", 5)
+			if err != nil {
+				t.Fatalf("FetchIssue failed: %v", err)
+			}
+
+			if requested != tt.wantRequested {
+				t.Errorf("sub_issues requested = %v, want %v", requested, tt.wantRequested)
+			}
+			if len(issue.SubIssues) != tt.wantSubIssues {
+				t.Fatalf("expected %d sub-issues, got %+v", tt.wantSubIssues, issue.SubIssues)
+			}
+			if tt.wantSubIssues > 0 && (issue.SubIssues[0].Number != 6 || issue.SubIssues[0].State != "closed" || issue.SubIssues[0].StateReason != "completed") {
+				t.Errorf("unexpected first sub-issue %+v", issue.SubIssues[0])
+			}
+			if issue.Parent == nil || issue.Parent.Number != 1 || issue.Parent.Title != "Roadmap" {
+				t.Errorf("unexpected parent %+v", issue.Parent)
+			}
+			if len(issue.Tasks) != 2 || !issue.Tasks[0].Done || issue.Tasks[1].Done {
+				t.Errorf("unexpected tasks %+v", issue.Tasks)
+			}
+
+			completed, total := issue.Progress()
+			wantCompleted, wantTotal := 1+tt.wantSubIssues/2, 2+tt.wantSubIssues
+			if completed != wantCompleted || total != wantTotal {
+				t.Errorf("Progress() = %d/%d, want %d/%d", completed, total, wantCompleted, wantTotal)
+			}
+		})
+	}
+}
+
+func TestFetchIssue_NoParent(t *testing.T) {
+	parentRequested := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/5":
+			json.NewEncoder(w).Encode(map[string]interface{}{"title": "Standalone", "parent_issue_url": nil})
+		case "/repos/owner/repo/issues/5/parent":
+			parentRequested = true
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": "Not Found"})
+		default:
+			json.NewEncoder(w).Encode([]interface{}{})
+		}
+	}))
+	defer mockServer.Close()
+
+	issue, err := NewClient("", WithBaseURL(mockServer.URL)).FetchIssue("owner", "repo", 5)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+	if issue.Parent != nil || len(issue.SubIssues) != 0 {
+		t.Errorf("expected no parent or sub-issues, got %+v / %+v", issue.Parent, issue.SubIssues)
+	}
+	if parentRequested {
+		t.Error("parent should not be requested when parent_issue_url is empty")
+	}
+}
@@ -0,0 +1,444 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FetchDiscussion 获取 GitHub Discussion（使用 GraphQL）
+//
+// 评论和回复按游标分页获取：首个查询取回每个连接的第一页，
+// 之后只为实际还有下一页的连接发送后续查询。reactions 通过 reactionGroups 直接取得各类型的总数，无需分页。
+func (c *Client) FetchDiscussion(owner, repo string, number int) (*Discussion, error) {
+	return c.FetchDiscussionContext(context.Background(), owner, repo, number)
+}
+
+// FetchDiscussionContext 获取 GitHub Discussion，ctx 取消时中止进行中的请求
+func (c *Client) FetchDiscussionContext(ctx context.Context, owner, repo string, number int) (*Discussion, error) {
+	d, err := c.queryDiscussion(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// 顶层评论翻页
+	nodes := d.Comments.Nodes
+	pageInfo := d.Comments.PageInfo
+	for pageInfo.HasNextPage {
+		page, err := c.queryComments(ctx, d.ID, pageInfo.EndCursor)
+		if err != nil {
+			return nil, fmt.Errorf("fetch discussion comments: %w", err)
+		}
+		nodes = append(nodes, page.Nodes...)
+		pageInfo = page.PageInfo
+	}
+
+	// 补全每条评论中溢出的回复
+	for i := range nodes {
+		if err := c.completeReplies(ctx, &nodes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// 确定状态
+	state := "open"
+	if d.ClosedAt != nil {
+		state = "closed"
+	}
+
+	// 构建评论树：顶层评论按时间排序，回复嵌套在各自的顶层评论下
+	comments := make([]Comment, len(nodes))
+	for i, node := range nodes {
+		comments[i] = node.toComment()
+	}
+	sortByCreatedAt(comments)
+
+	discussion := &Discussion{
+		Title:             d.Title,
+		URL:               d.URL,
+		User:              d.Author.toUser(),
+		AuthorAssociation: d.AuthorAssociation,
+		CreatedAt:         d.CreatedAt,
+		EditHistory:       d.toEditHistory(),
+		State:             state,
+		Metadata:          d.toMetadata(),
+		Body:              d.Body,
+		Reactions:         d.ReactionGroups.toReactions(),
+		Comments:          comments,
+		UpvoteCount:       d.UpvoteCount,
+		AnswerChosenAt:    d.AnswerChosenAt,
+	}
+	if d.Category != nil {
+		discussion.Category = d.Category.Name
+	}
+	if d.AnswerChosenBy != nil {
+		discussion.AnswerChosenBy = d.AnswerChosenBy.toUser()
+	}
+
+	// 最后编辑信息和隐藏状态已随查询返回，历史版本需要按节点批量获取，失败时忽略
+	if c.editHistory {
+		if nodes, err := c.fetchContentNodes(ctx, append([]string{d.ID}, commentNodeIDs(comments)...)); err == nil {
+			if node, ok := nodes[d.ID]; ok {
+				discussion.EditHistory = node.toEditHistory()
+			}
+			applyContentNodes(discussion.Comments, nodes)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return discussion, nil
+}
+
+// discussionQuery 查询 Discussion 及其第一页顶层评论
+const discussionQuery = `query($owner: String!, $name: String!, $number: Int!) {
+	repository(owner: $owner, name: $name) {
+		discussion(number: $number) {
+			id
+			title
+			url
+			author {
+				__typename
+				login
+				url
+			}
+			authorAssociation
+			createdAt
+			` + editFields + `
+			closedAt
+			body
+			upvoteCount
+			category {
+				name
+			}
+			answerChosenAt
+			answerChosenBy {
+				__typename
+				login
+				url
+			}
+			locked
+			stateReason
+			labels(first: 100) {
+				nodes {
+					name
+				}
+			}
+			` + reactionGroupsFields + `
+			comments(first: 100) {
+				totalCount
+				` + pageInfoFields + `
+				nodes {
+					` + topLevelCommentFields + `
+				}
+			}
+		}
+	}
+}`
+
+// queryDiscussion 查询 Discussion 及其第一页顶层评论
+func (c *Client) queryDiscussion(ctx context.Context, owner, repo string, number int) (*graphQLDiscussion, error) {
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": number,
+	}
+
+	var data struct {
+		Repository struct {
+			Discussion *graphQLDiscussion `json:"discussion"`
+		} `json:"repository"`
+	}
+
+	if err := c.graphQL(ctx, discussionQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Repository.Discussion == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return data.Repository.Discussion, nil
+}
+
+// commentsQuery 查询 Discussion 的一页顶层评论
+const commentsQuery = `query($id: ID!, $after: String) {
+	node(id: $id) {
+		... on Discussion {
+			comments(first: 100, after: $after) {
+				totalCount
+				` + pageInfoFields + `
+				nodes {
+					` + topLevelCommentFields + `
+				}
+			}
+		}
+	}
+}`
+
+// queryComments 查询 Discussion 的下一页顶层评论
+func (c *Client) queryComments(ctx context.Context, discussionID, after string) (*graphQLDiscussionComments, error) {
+	variables := map[string]interface{}{
+		"id":    discussionID,
+		"after": graphQLCursor(after),
+	}
+
+	var data struct {
+		Node *struct {
+			Comments graphQLDiscussionComments `json:"comments"`
+		} `json:"node"`
+	}
+
+	if err := c.graphQL(ctx, commentsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Node == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &data.Node.Comments, nil
+}
+
+// completeReplies 补全评论中溢出的回复
+func (c *Client) completeReplies(ctx context.Context, node *graphQLDiscussionComment) error {
+	for node.Replies.PageInfo.HasNextPage {
+		page, err := c.queryReplies(ctx, node.ID, node.Replies.PageInfo.EndCursor)
+		if err != nil {
+			return fmt.Errorf("fetch replies of comment %s: %w", node.ID, err)
+		}
+		node.Replies.Nodes = append(node.Replies.Nodes, page.Nodes...)
+		node.Replies.PageInfo = page.PageInfo
+	}
+	return nil
+}
+
+// repliesQuery 查询评论的一页回复
+const repliesQuery = `query($id: ID!, $after: String) {
+	node(id: $id) {
+		... on DiscussionComment {
+			replies(first: 100, after: $after) {
+				` + pageInfoFields + `
+				nodes {
+					` + discussionCommentFields + `
+				}
+			}
+		}
+	}
+}`
+
+// queryReplies 查询评论的下一页回复
+func (c *Client) queryReplies(ctx context.Context, commentID, after string) (*graphQLReplies, error) {
+	variables := map[string]interface{}{
+		"id":    commentID,
+		"after": graphQLCursor(after),
+	}
+
+	var data struct {
+		Node *struct {
+			Replies graphQLReplies `json:"replies"`
+		} `json:"node"`
+	}
+
+	if err := c.graphQL(ctx, repliesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Node == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &data.Node.Replies, nil
+}
+
+// pageInfoFields GraphQL 连接的分页信息字段
+const pageInfoFields = `pageInfo {
+	hasNextPage
+	endCursor
+}`
+
+// reactionGroupsFields 按类型汇总的 reactions 字段
+// reactionGroups 不是分页连接，不会像 reactions(first:) 那样在嵌套查询中成倍占用节点配额
+const reactionGroupsFields = `reactionGroups {
+	content
+	users {
+		totalCount
+	}
+}`
+
+// discussionCommentFields Discussion 评论和回复共用的 GraphQL 字段
+const discussionCommentFields = `id
+	author {
+		__typename
+		login
+		url
+	}
+	authorAssociation
+	createdAt
+	` + editFields + `
+	` + minimizedFields + `
+	deletedAt
+	body
+	isAnswer
+	upvoteCount
+	` + reactionGroupsFields
+
+// topLevelCommentFields 顶层评论的 GraphQL 字段（含第一页回复）
+// 嵌套在 comments(first: 100) 中，回复每页取 50 条，使单个查询的节点数（100 + 100×50）远低于 GitHub 的 500,000 上限
+const topLevelCommentFields = discussionCommentFields + `
+	replies(first: 50) {
+		` + pageInfoFields + `
+		nodes {
+			` + discussionCommentFields + `
+		}
+	}`
+
+// graphQLPageInfo GraphQL 连接的分页信息
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphQLActor GraphQL 返回的用户（Actor），账号已删除时为 null
+type graphQLActor struct {
+	Typename string `json:"__typename"` // "User", "Bot", "Mannequin", "Organization" 等
+	Login    string `json:"login"`
+	URL      string `json:"url"`
+}
+
+// toUser 转换为通用用户，null（已删除的账号）转换为 ghost
+func (a *graphQLActor) toUser() User {
+	if a == nil || a.Login == "" {
+		return ghostUser()
+	}
+
+	user := User{Login: a.Login, HTMLURL: a.URL, Kind: UserKindUser}
+	switch a.Typename {
+	case "Bot":
+		user.Kind = UserKindBot
+	case "Mannequin":
+		user.Kind = UserKindMannequin
+	}
+	return user
+}
+
+// graphQLReactionGroups GraphQL 返回的按类型汇总的 reactions（reactionGroupsFields）
+type graphQLReactionGroups []struct {
+	Content string `json:"content"`
+	Users   struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"users"`
+}
+
+// graphQLReplies GraphQL 返回的回复连接
+type graphQLReplies struct {
+	PageInfo graphQLPageInfo            `json:"pageInfo"`
+	Nodes    []graphQLDiscussionComment `json:"nodes"`
+}
+
+// graphQLDiscussion GraphQL 返回的 Discussion（含一页顶层评论）
+type graphQLDiscussion struct {
+	ID                string            `json:"id"`
+	Title             string            `json:"title"`
+	URL               string            `json:"url"`
+	Author            *graphQLActor     `json:"author"`
+	AuthorAssociation AuthorAssociation `json:"authorAssociation"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	graphQLEdited
+	ClosedAt    *time.Time `json:"closedAt"`
+	Body        string     `json:"body"`
+	UpvoteCount int        `json:"upvoteCount"`
+	Category    *struct {
+		Name string `json:"name"`
+	} `json:"category"`
+	AnswerChosenAt *time.Time    `json:"answerChosenAt"`
+	AnswerChosenBy *graphQLActor `json:"answerChosenBy"`
+	Locked         bool          `json:"locked"`
+	StateReason    string        `json:"stateReason"`
+	Labels         struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	ReactionGroups graphQLReactionGroups     `json:"reactionGroups"`
+	Comments       graphQLDiscussionComments `json:"comments"`
+}
+
+// graphQLDiscussionComments GraphQL 返回的一页顶层评论
+type graphQLDiscussionComments struct {
+	TotalCount int                        `json:"totalCount"`
+	PageInfo   graphQLPageInfo            `json:"pageInfo"`
+	Nodes      []graphQLDiscussionComment `json:"nodes"`
+}
+
+// toMetadata 转换为通用的 Metadata（状态原因转为小写，与 REST 保持一致）
+func (d *graphQLDiscussion) toMetadata() Metadata {
+	metadata := Metadata{
+		ClosedAt:     d.ClosedAt,
+		StateReason:  strings.ToLower(d.StateReason),
+		Locked:       d.Locked,
+		CommentCount: d.Comments.TotalCount,
+	}
+	for _, label := range d.Labels.Nodes {
+		metadata.Labels = append(metadata.Labels, label.Name)
+	}
+	return metadata
+}
+
+// graphQLDiscussionComment GraphQL 返回的 Discussion 评论（回复使用相同结构）
+type graphQLDiscussionComment struct {
+	ID                string            `json:"id"`
+	Author            *graphQLActor     `json:"author"`
+	AuthorAssociation AuthorAssociation `json:"authorAssociation"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	graphQLEdited
+	graphQLMinimized
+	DeletedAt      *time.Time            `json:"deletedAt"`
+	Body           string                `json:"body"`
+	IsAnswer       bool                  `json:"isAnswer"`
+	UpvoteCount    int                   `json:"upvoteCount"`
+	ReactionGroups graphQLReactionGroups `json:"reactionGroups"`
+	Replies        graphQLReplies        `json:"replies"`
+}
+
+// toComment 转换为通用评论，回复按时间排序放入 Replies
+func (n graphQLDiscussionComment) toComment() Comment {
+	comment := Comment{
+		ID:                0, // GraphQL ID 是字符串，暂时设为 0
+		NodeID:            n.ID,
+		User:              n.Author.toUser(),
+		AuthorAssociation: n.AuthorAssociation,
+		CreatedAt:         n.CreatedAt,
+		EditHistory:       n.toEditHistory(),
+		Body:              n.Body,
+		Reactions:         n.ReactionGroups.toReactions(),
+		Deleted:           n.DeletedAt != nil,
+		IsAnswer:          n.IsAnswer,
+		UpvoteCount:       n.UpvoteCount,
+	}
+
+	n.graphQLMinimized.apply(&comment)
+
+	if len(n.Replies.Nodes) > 0 {
+		comment.Replies = make([]Comment, len(n.Replies.Nodes))
+		for i, reply := range n.Replies.Nodes {
+			comment.Replies[i] = reply.toComment()
+		}
+		sortByCreatedAt(comment.Replies)
+	}
+
+	return comment
+}
+
+// toReactions 转换为通用的 reactions（跳过数量为 0 的类型）
+func (g graphQLReactionGroups) toReactions() []Reaction {
+	reactions := []Reaction{}
+	for _, group := range g {
+		if group.Users.TotalCount > 0 {
+			reactions = append(reactions, Reaction{Content: convertReactionContent(group.Content), Count: group.Users.TotalCount})
+		}
+	}
+	return reactions
+}
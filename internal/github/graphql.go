@@ -0,0 +1,137 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GraphQL 错误类型（GitHub 在 errors[].type 中返回）
+const (
+	GraphQLErrorNotFound    = "NOT_FOUND"
+	GraphQLErrorForbidden   = "FORBIDDEN"
+	GraphQLErrorRateLimited = "RATE_LIMITED"
+)
+
+// GraphQLError GraphQL 响应 errors 数组中的单个错误
+type GraphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// Error 实现 error 接口
+func (e GraphQLError) Error() string {
+	if e.Type == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// Unwrap 将已知错误类型映射到包内的哨兵错误，便于 errors.Is 判断
+func (e GraphQLError) Unwrap() error {
+	switch e.Type {
+	case GraphQLErrorNotFound:
+		return ErrResourceNotFound
+	case GraphQLErrorForbidden:
+		return ErrForbidden
+	case GraphQLErrorRateLimited:
+		return ErrAPIRateLimit
+	default:
+		return nil
+	}
+}
+
+// GraphQLErrors GraphQL 响应中的全部错误
+type GraphQLErrors []GraphQLError
+
+// Error 实现 error 接口
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// Unwrap 返回每个错误，使 errors.Is / errors.As 能匹配其中任意一个
+func (e GraphQLErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// graphQLRequest GraphQL 请求体
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLResponse GraphQL 响应体
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// graphQL 发送参数化的 GraphQL 查询，将 data 字段解码到 v
+// 响应中包含 errors 时返回 GraphQLErrors；若同时返回了 data（部分结果），仍会先解码到 v
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	bodyBytes, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	body, _, err := c.send(ctx, "POST", c.graphqlURL, bodyBytes)
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		if len(response.Errors) > 0 {
+			return response.Errors
+		}
+		return fmt.Errorf("parse response: missing data")
+	}
+
+	if err := json.Unmarshal(response.Data, v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+
+	return nil
+}
+
+// isPartialNotFound 判断 err 是否只包含指向 field 下单个元素的 NOT_FOUND 错误（如 path 为 ["nodes", 1]）
+// 这类错误表示个别节点已删除或不可见，data 中其余结果仍然有效
+func isPartialNotFound(err error, field string) bool {
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) == 0 {
+		return false
+	}
+	for _, gqlErr := range gqlErrs {
+		if gqlErr.Type != GraphQLErrorNotFound || len(gqlErr.Path) < 2 || gqlErr.Path[0] != field {
+			return false
+		}
+	}
+	return true
+}
+
+// graphQLCursor 将游标转换为查询变量，空游标（第一页）对应 null
+func graphQLCursor(after string) interface{} {
+	if after == "" {
+		return nil
+	}
+	return after
+}
@@ -0,0 +1,302 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGraphQL_Variables 测试查询参数通过 variables 发送，而不是拼接进查询文本
+func TestGraphQL_Variables(t *testing.T) {
+	var payload struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"discussion": map[string]interface{}{"title": "Quoted"},
+				},
+			},
+		})
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	discussion, err := client.FetchDiscussion("own\"er", "re\"po", 42)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+
+	if discussion.Title != "Quoted" {
+		t.Errorf("expected title Quoted, got %q", discussion.Title)
+	}
+
+	if payload.Variables["owner"] != "own\"er" || payload.Variables["name"] != "re\"po" {
+		t.Errorf("expected owner/name passed as variables, got %+v", payload.Variables)
+	}
+
+	if payload.Variables["number"] != float64(42) {
+		t.Errorf("expected number 42, got %v", payload.Variables["number"])
+	}
+
+	if after, ok := payload.Variables["after"]; ok {
+		t.Errorf("expected no cursor in the discussion query, got %v", after)
+	}
+}
+
+// TestGraphQL_Errors 测试 errors 数组被解析为带类型的错误
+func TestGraphQL_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		errorType string
+		wantErr   error
+	}{
+		{name: "not found", errorType: "NOT_FOUND", wantErr: ErrResourceNotFound},
+		{name: "forbidden", errorType: "FORBIDDEN", wantErr: ErrForbidden},
+		{name: "unknown type", errorType: "SOMETHING_ELSE", wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"repository": map[string]interface{}{"discussion": nil},
+					},
+					"errors": []map[string]interface{}{
+						{
+							"type":    tt.errorType,
+							"message": "boom",
+							"path":    []interface{}{"repository", "discussion"},
+						},
+					},
+				})
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("", WithBaseURL(mockServer.URL))
+
+			_, err := client.FetchDiscussion("owner", "repo", 1)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var gqlErrs GraphQLErrors
+			if !errors.As(err, &gqlErrs) || len(gqlErrs) != 1 || gqlErrs[0].Type != tt.errorType {
+				t.Errorf("expected GraphQLErrors with type %s, got %v", tt.errorType, err)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected errors.Is(err, %v), got %v", tt.wantErr, err)
+			}
+
+			for _, sentinel := range []error{ErrResourceNotFound, ErrForbidden, ErrAPIRateLimit} {
+				if sentinel != tt.wantErr && errors.Is(err, sentinel) {
+					t.Errorf("error %v should not match %v", err, sentinel)
+				}
+			}
+		})
+	}
+}
+
+// TestGraphQL_RateLimit 测试 GraphQL 限流（200 + RATE_LIMITED）按限流响应头等待后重试
+func TestGraphQL_RateLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		rateLimitWait time.Duration
+		wantErr       bool
+		wantSleeps    []time.Duration
+	}{
+		{name: "fail fast by default", rateLimitWait: 0, wantErr: true},
+		{name: "wait until reset and retry", rateLimitWait: time.Hour, wantSleeps: []time.Duration{10 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", "application/json")
+				if requests == 1 {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", "1700000600")
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"errors": []map[string]interface{}{
+							{"type": "RATE_LIMITED", "message": "API rate limit exceeded"},
+						},
+					})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"repository": map[string]interface{}{
+							"discussion": map[string]interface{}{"title": "ok"},
+						},
+					},
+				})
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("", WithBaseURL(mockServer.URL), WithRateLimitWait(tt.rateLimitWait))
+			client.now = func() time.Time { return now }
+			var sleeps []time.Duration
+			client.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			discussion, err := client.FetchDiscussion("owner", "repo", 1)
+
+			if tt.wantErr {
+				var rateLimitErr *RateLimitError
+				if !errors.Is(err, ErrAPIRateLimit) || !errors.As(err, &rateLimitErr) {
+					t.Fatalf("expected RateLimitError, got %v", err)
+				}
+				if !rateLimitErr.Reset.Equal(now.Add(10 * time.Minute)) {
+					t.Errorf("expected reset time in error, got %v", rateLimitErr.Reset)
+				}
+				if requests != 1 || len(sleeps) != 0 {
+					t.Errorf("expected a single request without sleep, got %d requests, sleeps %v", requests, sleeps)
+				}
+				return
+			}
+
+			if err != nil || discussion.Title != "ok" {
+				t.Fatalf("expected retry to succeed, got %v", err)
+			}
+			if len(sleeps) != len(tt.wantSleeps) || sleeps[0] != tt.wantSleeps[0] {
+				t.Errorf("expected sleeps %v, got %v", tt.wantSleeps, sleeps)
+			}
+		})
+	}
+}
+
+// TestFetchContentNodes_PartialNotFound 测试 nodes(ids:) 中个别节点 NOT_FOUND 时保留其余节点的数据
+func TestFetchContentNodes_PartialNotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		errors    []map[string]interface{}
+		wantErr   bool
+		wantNodes int
+	}{
+		{
+			name: "deleted node",
+			errors: []map[string]interface{}{
+				{"type": "NOT_FOUND", "message": "Could not resolve to a node with the global id of 'B'", "path": []interface{}{"nodes", 1}},
+			},
+			wantNodes: 1,
+		},
+		{
+			name: "other error",
+			errors: []map[string]interface{}{
+				{"type": "NOT_FOUND", "message": "Could not resolve to a node with the global id of 'B'", "path": []interface{}{"nodes", 1}},
+				{"type": "FORBIDDEN", "message": "Resource not accessible", "path": []interface{}{"nodes", 0}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"nodes": []interface{}{
+							map[string]interface{}{"id": "A", "lastEditedAt": "2024-05-02T00:00:00Z", "isMinimized": true, "minimizedReason": "OUTDATED"},
+							nil,
+						},
+					},
+					"errors": tt.errors,
+				})
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("token", WithBaseURL(mockServer.URL))
+			nodes, err := client.fetchContentNodes(context.Background(), []string{"A", "B"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetchContentNodes error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(nodes) != tt.wantNodes {
+				t.Fatalf("expected %d nodes, got %v", tt.wantNodes, nodes)
+			}
+			if tt.wantNodes > 0 && (!nodes["A"].IsMinimized || nodes["A"].LastEditedAt == nil) {
+				t.Errorf("expected data of node A to be kept, got %+v", nodes["A"])
+			}
+		})
+	}
+}
+
+// TestFetchContentNodes_RevisionPages 测试编辑历史超过一页时继续翻页，保留最早的原始版本
+func TestFetchContentNodes_RevisionPages(t *testing.T) {
+	edit := func(editedAt, diff string) map[string]interface{} {
+		return map[string]interface{}{"editedAt": editedAt, "editor": map[string]interface{}{"login": "alice"}, "diff": diff}
+	}
+
+	var followUp map[string]interface{}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, ok := payload.Variables["ids"]; ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"id": "A", "userContentEdits": map[string]interface{}{
+							"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "edits1"},
+							"nodes":    []interface{}{edit("2024-05-03T00:00:00Z", "v3"), edit("2024-05-02T00:00:00Z", "v2")},
+						}},
+					},
+				},
+			})
+			return
+		}
+
+		followUp = payload.Variables
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"node": map[string]interface{}{"userContentEdits": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": false},
+					"nodes":    []interface{}{edit("2024-05-01T00:00:00Z", "v1")},
+				}},
+			},
+		})
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("token", WithBaseURL(mockServer.URL), WithEditHistory(true))
+	nodes, err := client.fetchContentNodes(context.Background(), []string{"A"})
+	if err != nil {
+		t.Fatalf("fetchContentNodes failed: %v", err)
+	}
+
+	if followUp["id"] != "A" || followUp["after"] != "edits1" {
+		t.Errorf("expected follow-up query for node A after edits1, got %+v", followUp)
+	}
+
+	revisions := nodes["A"].toEditHistory().Revisions
+	if len(revisions) != 3 || revisions[0].Body != "v1" || revisions[2].Body != "v3" {
+		t.Errorf("expected all revisions oldest first, got %+v", revisions)
+	}
+}
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// nodesBatchSize 单次 nodes(ids:) 查询的最大节点数（GitHub 限制为 100）
+const nodesBatchSize = 100
+
+// editFields 可编辑内容（GraphQL Comment 接口）的最后编辑信息字段
+const editFields = `lastEditedAt
+	editor {
+		__typename
+		login
+		url
+	}`
+
+// revisionFields 编辑历史的第一页（从最新版本开始），diff 为该版本的完整内容
+const revisionFields = `userContentEdits(first: 100) {
+		` + userContentEditFields + `
+	}`
+
+// userContentEditFields 编辑历史连接的分页信息和版本字段
+const userContentEditFields = pageInfoFields + `
+	nodes {
+		editedAt
+		editor {
+			__typename
+			login
+			url
+		}
+		diff
+	}`
+
+// userContentEditsQuery 查询内容节点的一页编辑历史
+const userContentEditsQuery = `query($id: ID!, $after: String) {
+	node(id: $id) {
+		... on Comment {
+			userContentEdits(first: 100, after: $after) {
+				` + userContentEditFields + `
+			}
+		}
+	}
+}`
+
+// minimizedFields 可折叠内容（GraphQL Minimizable 接口）的隐藏状态字段
+const minimizedFields = `isMinimized
+	minimizedReason`
+
+// contentNodesQuery 返回批量查询节点编辑信息和隐藏状态的 GraphQL 查询
+func contentNodesQuery(withRevisions bool) string {
+	fields := editFields
+	if withRevisions {
+		fields += "\n\t" + revisionFields
+	}
+	return `query($ids: [ID!]!) {
+	nodes(ids: $ids) {
+		... on Comment {
+			id
+			` + fields + `
+		}
+		... on Minimizable {
+			` + minimizedFields + `
+		}
+	}
+}`
+}
+
+// graphQLMinimized 可折叠内容的隐藏状态（minimizedFields）
+type graphQLMinimized struct {
+	IsMinimized     bool   `json:"isMinimized"`
+	MinimizedReason string `json:"minimizedReason"`
+}
+
+// apply 将隐藏状态写入评论（原因统一为小写，如 spam、off-topic）
+func (m graphQLMinimized) apply(comment *Comment) {
+	comment.Minimized = m.IsMinimized
+	comment.MinimizedReason = ""
+	if m.IsMinimized {
+		comment.MinimizedReason = strings.ToLower(m.MinimizedReason)
+	}
+}
+
+// graphQLContentNode GraphQL 返回的内容节点（Issue、PR、评论、Review 等）
+type graphQLContentNode struct {
+	ID string `json:"id"`
+	graphQLEdited
+	graphQLMinimized
+	UserContentEdits graphQLUserContentEdits `json:"userContentEdits"`
+}
+
+// graphQLUserContentEdits GraphQL 返回的一页编辑历史（按时间倒序）
+type graphQLUserContentEdits struct {
+	PageInfo graphQLPageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		EditedAt time.Time     `json:"editedAt"`
+		Editor   *graphQLActor `json:"editor"`
+		Diff     string        `json:"diff"`
+	} `json:"nodes"`
+}
+
+// graphQLEdited 可编辑内容的最后编辑信息（editFields）
+type graphQLEdited struct {
+	LastEditedAt *time.Time    `json:"lastEditedAt"`
+	Editor       *graphQLActor `json:"editor"`
+}
+
+// toEditHistory 转换为通用的编辑信息（不含历史版本）
+func (e graphQLEdited) toEditHistory() EditHistory {
+	history := EditHistory{EditedAt: e.LastEditedAt}
+	if e.Editor != nil {
+		history.Editor = e.Editor.toUser()
+	}
+	return history
+}
+
+// toEditHistory 转换为通用的编辑信息，历史版本按时间升序排列
+func (n graphQLContentNode) toEditHistory() EditHistory {
+	history := n.graphQLEdited.toEditHistory()
+	for _, edit := range n.UserContentEdits.Nodes {
+		revision := Revision{EditedAt: edit.EditedAt, Body: edit.Diff}
+		if edit.Editor != nil {
+			revision.Editor = edit.Editor.toUser()
+		}
+		history.Revisions = append(history.Revisions, revision)
+	}
+	sort.SliceStable(history.Revisions, func(i, j int) bool {
+		return history.Revisions[i].EditedAt.Before(history.Revisions[j].EditedAt)
+	})
+	return history
+}
+
+// fetchContentNodes 通过 GraphQL nodes(ids:) 批量获取编辑信息和隐藏状态，返回以节点 ID 为键的结果
+// 启用编辑历史时同时获取全部历史版本（超过一页时逐个节点翻页，以保留最早的原始版本）
+func (c *Client) fetchContentNodes(ctx context.Context, ids []string) (map[string]graphQLContentNode, error) {
+	nodes := make(map[string]graphQLContentNode)
+	query := contentNodesQuery(c.editHistory)
+
+	// 跳过缺少节点 ID 的条目，没有可查询的节点时不发送请求
+	valid := []string{}
+	for _, id := range ids {
+		if id != "" {
+			valid = append(valid, id)
+		}
+	}
+	ids = valid
+
+	for start := 0; start < len(ids); start += nodesBatchSize {
+		end := start + nodesBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var data struct {
+			Nodes []*graphQLContentNode `json:"nodes"`
+		}
+		variables := map[string]interface{}{"ids": ids[start:end]}
+		// 已删除的节点会附带 NOT_FOUND 错误，其余节点的数据仍然可用
+		if err := c.graphQL(ctx, query, variables, &data); err != nil && !isPartialNotFound(err, "nodes") {
+			return nil, err
+		}
+
+		// 无权访问或已删除的节点返回 null
+		for _, node := range data.Nodes {
+			if node == nil || node.ID == "" {
+				continue
+			}
+			if err := c.completeUserContentEdits(ctx, node.ID, &node.UserContentEdits); err != nil {
+				return nil, err
+			}
+			nodes[node.ID] = *node
+		}
+	}
+
+	return nodes, nil
+}
+
+// completeUserContentEdits 为编辑历史连接补全后续页
+func (c *Client) completeUserContentEdits(ctx context.Context, nodeID string, edits *graphQLUserContentEdits) error {
+	for edits.PageInfo.HasNextPage {
+		variables := map[string]interface{}{
+			"id":    nodeID,
+			"after": graphQLCursor(edits.PageInfo.EndCursor),
+		}
+
+		var data struct {
+			Node *struct {
+				UserContentEdits graphQLUserContentEdits `json:"userContentEdits"`
+			} `json:"node"`
+		}
+		if err := c.graphQL(ctx, userContentEditsQuery, variables, &data); err != nil {
+			return fmt.Errorf("fetch edit history of %s: %w", nodeID, err)
+		}
+		if data.Node == nil {
+			return fmt.Errorf("fetch edit history of %s: %w", nodeID, ErrResourceNotFound)
+		}
+
+		edits.Nodes = append(edits.Nodes, data.Node.UserContentEdits.Nodes...)
+		edits.PageInfo = data.Node.UserContentEdits.PageInfo
+	}
+	return nil
+}
+
+// commentNodeIDs 收集评论（包括嵌套的子评论）的节点 ID
+func commentNodeIDs(comments []Comment) []string {
+	ids := []string{}
+	for _, comment := range comments {
+		if comment.NodeID != "" {
+			ids = append(ids, comment.NodeID)
+		}
+		ids = append(ids, commentNodeIDs(comment.Replies)...)
+	}
+	return ids
+}
+
+// applyContentNodes 将编辑信息和隐藏状态写入对应的评论（包括嵌套的子评论）
+func applyContentNodes(comments []Comment, nodes map[string]graphQLContentNode) {
+	for i := range comments {
+		if node, ok := nodes[comments[i].NodeID]; ok {
+			comments[i].EditHistory = node.toEditHistory()
+			node.apply(&comments[i])
+		}
+		applyContentNodes(comments[i].Replies, nodes)
+	}
+}
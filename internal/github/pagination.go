@@ -0,0 +1,101 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxPerPage GitHub 列表接口允许的最大每页条目数
+const maxPerPage = 100
+
+// getAll 获取列表接口的全部数据，跟随 Link 头中的 rel="next" 逐页请求
+func getAll[T any](ctx context.Context, c *Client, rawURL string) ([]T, error) {
+	all := []T{}
+	err := c.paginate(ctx, rawURL, func(body []byte) error {
+		var page []T
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// paginate 依次请求列表接口的每一页，并将响应体交给 handle 处理
+// 适用于响应体不是纯数组的列表接口（如 check-runs）
+func (c *Client) paginate(ctx context.Context, rawURL string, handle func(body []byte) error) error {
+	next, err := withPerPage(rawURL, c.perPage)
+	if err != nil {
+		return err
+	}
+
+	visited := make(map[string]bool)
+	for next != "" && !visited[next] {
+		visited[next] = true
+
+		body, header, err := c.send(ctx, "GET", next, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := handle(body); err != nil {
+			return err
+		}
+
+		next = nextPageURL(header.Get("Link"))
+	}
+
+	return nil
+}
+
+// withPerPage 为 URL 添加 per_page 参数（已存在时保持不变）
+func withPerPage(rawURL string, perPage int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse URL %q: %w", rawURL, err)
+	}
+
+	query := u.Query()
+	if query.Get("per_page") == "" {
+		query.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// nextPageURL 解析 Link 头，返回 rel="next" 对应的 URL，没有下一页时返回空字符串
+//
+// Link 头格式：
+//
+//	<https://api.github.com/...?page=2>; rel="next", <https://api.github.com/...?page=5>; rel="last"
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range segments[1:] {
+			param = strings.TrimSpace(param)
+			if param == `rel="next"` || param == "rel=next" {
+				return strings.Trim(target, "<>")
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,311 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestNextPageURL 测试 Link 头解析
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "next and last",
+			link: `<https://api.github.com/repositories/1/issues/1/comments?page=2>; rel="next", <https://api.github.com/repositories/1/issues/1/comments?page=5>; rel="last"`,
+			want: "https://api.github.com/repositories/1/issues/1/comments?page=2",
+		},
+		{
+			name: "next is not the first entry",
+			link: `<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next"`,
+			want: "https://api.github.com/x?page=3",
+		},
+		{
+			name: "last page has no next",
+			link: `<https://api.github.com/x?page=1>; rel="first", <https://api.github.com/x?page=4>; rel="prev"`,
+			want: "",
+		},
+		{
+			name: "empty header",
+			link: "",
+			want: "",
+		},
+		{
+			name: "malformed entry",
+			link: `https://api.github.com/x?page=2; rel="next"`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFetchIssue_PaginatedComments 测试评论跨多页时全部获取
+func TestFetchIssue_PaginatedComments(t *testing.T) {
+	var perPages []string
+
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/issues/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"title":      "Long Issue",
+				"html_url":   "https://github.com/test-owner/test-repo/issues/1",
+				"created_at": "2024-01-01T00:00:00Z",
+				"state":      "open",
+			})
+		case "/repos/test-owner/test-repo/issues/1/comments":
+			perPages = append(perPages, r.URL.Query().Get("per_page"))
+			page := r.URL.Query().Get("page")
+			if page == "" {
+				page = "1"
+			}
+			if page != "3" {
+				var nextPage int
+				fmt.Sscanf(page, "%d", &nextPage)
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=2&page=%d>; rel="next"`,
+					mockServer.URL, r.URL.Path, nextPage+1))
+			}
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "body": "comment on page " + page, "created_at": "2024-01-02T00:00:00Z"},
+				{"id": 2, "body": "another on page " + page, "created_at": "2024-01-02T00:00:00Z"},
+			})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL), WithPerPage(2))
+
+	issue, err := client.FetchIssue("test-owner", "test-repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue failed: %v", err)
+	}
+
+	if len(issue.Comments) != 6 {
+		t.Fatalf("expected 6 comments across 3 pages, got %d", len(issue.Comments))
+	}
+
+	if issue.Comments[5].Body != "another on page 3" {
+		t.Errorf("expected last comment from page 3, got %q", issue.Comments[5].Body)
+	}
+
+	for i, perPage := range perPages {
+		if perPage != "2" {
+			t.Errorf("request %d: expected per_page=2, got %q", i, perPage)
+		}
+	}
+}
+
+// TestWithPerPage 测试 per_page 取值范围限制
+func TestWithPerPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		perPage int
+		want    int
+	}{
+		{name: "below min", perPage: 0, want: 1},
+		{name: "in range", perPage: 50, want: 50},
+		{name: "above max", perPage: 500, want: maxPerPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("", WithPerPage(tt.perPage))
+			if client.perPage != tt.want {
+				t.Errorf("perPage = %d, want %d", client.perPage, tt.want)
+			}
+		})
+	}
+}
+
+// TestFetchDiscussion_CursorPagination 测试 Discussion 评论和回复的游标分页，reactions 直接取自 reactionGroups
+func TestFetchDiscussion_CursorPagination(t *testing.T) {
+	comment := func(id, login, createdAt string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":        id,
+			"author":    map[string]interface{}{"login": login},
+			"createdAt": createdAt,
+			"body":      "comment " + id,
+		}
+	}
+	page := func(hasNext bool, cursor string) map[string]interface{} {
+		return map[string]interface{}{"hasNextPage": hasNext, "endCursor": cursor}
+	}
+	reactionGroup := func(content string, count int) map[string]interface{} {
+		return map[string]interface{}{"content": content, "users": map[string]interface{}{"totalCount": count}}
+	}
+
+	type graphQLPayload struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	var payloads []graphQLPayload
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload graphQLPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		query := payload.Query
+		payloads = append(payloads, payload)
+
+		var data map[string]interface{}
+		switch {
+		case payload.Variables["id"] == "D_1" && strings.Contains(query, "... on Discussion"):
+			// 第二页顶层评论
+			data = map[string]interface{}{"node": map[string]interface{}{
+				"comments": map[string]interface{}{
+					"pageInfo": page(false, ""),
+					"nodes":    []interface{}{comment("c2", "carol", "2024-01-03T00:00:00Z")},
+				},
+			}}
+		case payload.Variables["id"] == "c1" && strings.Contains(query, "DiscussionComment"):
+			// c1 的第二页回复
+			r3 := comment("r3", "replier", "2024-01-02T03:00:00Z")
+			r3["reactionGroups"] = []interface{}{reactionGroup("HEART", 1)}
+			data = map[string]interface{}{"node": map[string]interface{}{
+				"replies": map[string]interface{}{"pageInfo": page(false, ""), "nodes": []interface{}{r3}},
+			}}
+		default:
+			c1 := comment("c1", "alice", "2024-01-02T00:00:00Z")
+			// 超过 100 个 reactions 也无需额外查询
+			c1["reactionGroups"] = []interface{}{reactionGroup("THUMBS_UP", 250), reactionGroup("HEART", 1)}
+			r1 := comment("r1", "bob", "2024-01-02T01:00:00Z")
+			r2 := comment("r2", "bob", "2024-01-02T02:00:00Z")
+			c1["replies"] = map[string]interface{}{"pageInfo": page(true, "reply1"), "nodes": []interface{}{r1, r2}}
+			data = map[string]interface{}{"repository": map[string]interface{}{
+				"discussion": map[string]interface{}{
+					"id":        "D_1",
+					"title":     "Paginated",
+					"createdAt": "2024-01-01T00:00:00Z",
+					"comments":  map[string]interface{}{"pageInfo": page(true, "comments1"), "nodes": []interface{}{c1}},
+				},
+			}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL))
+
+	discussion, err := client.FetchDiscussion("owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchDiscussion failed: %v", err)
+	}
+
+	if len(payloads) != 3 {
+		t.Fatalf("expected 3 GraphQL queries, got %d", len(payloads))
+	}
+	for _, payload := range payloads {
+		if strings.Contains(payload.Query, "reactions(") {
+			t.Errorf("reactions should come from reactionGroups, got query %s", payload.Query)
+		}
+	}
+
+	// 后续页只查询顶层评论，不再重复查询 Discussion 本身
+	next := payloads[1]
+	if strings.Contains(next.Query, "repository(") || !strings.Contains(next.Query, "comments(first: 100, after: $after)") {
+		t.Errorf("expected a comments-only follow-up query, got %s", next.Query)
+	}
+	if next.Variables["id"] != "D_1" || next.Variables["after"] != "comments1" {
+		t.Errorf("expected discussion ID and cursor as variables, got %+v", next.Variables)
+	}
+
+	if len(discussion.Comments) != 2 || discussion.Comments[1].Body != "comment c2" {
+		t.Fatalf("expected comments from both pages, got %+v", discussion.Comments)
+	}
+
+	first := discussion.Comments[0]
+	if len(first.Replies) != 3 || first.Replies[2].Body != "comment r3" {
+		t.Errorf("expected 3 replies across pages, got %+v", first.Replies)
+	}
+
+	if len(first.Replies[2].Reactions) != 1 || first.Replies[2].Reactions[0].Count != 1 {
+		t.Errorf("expected reactions on paginated reply, got %+v", first.Replies[2].Reactions)
+	}
+
+	counts := map[string]int{}
+	for _, reaction := range first.Reactions {
+		counts[reaction.Content] = reaction.Count
+	}
+	if counts["+1"] != 250 || counts["heart"] != 1 {
+		t.Errorf("expected reaction totals from reactionGroups, got %+v", first.Reactions)
+	}
+}
+
+// graphQLNodeLimit GitHub 单个 GraphQL 查询允许的最大节点数
+const graphQLNodeLimit = 500000
+
+// graphQLNodeBudget 按 GitHub 的规则计算查询最多可能返回的节点数：
+// 每个带 first/last 的连接贡献其自身与所有外层连接页大小之积
+func graphQLNodeBudget(query string) int {
+	pageSize := regexp.MustCompile(`\b(?:first|last):\s*(\d+)`)
+	total := 0
+	multipliers := []int{1}
+	fieldStart := 0
+	for i, ch := range query {
+		switch ch {
+		case '{':
+			multiplier := multipliers[len(multipliers)-1]
+			if match := pageSize.FindStringSubmatch(query[fieldStart:i]); match != nil {
+				size, _ := strconv.Atoi(match[1])
+				multiplier *= size
+				total += multiplier
+			}
+			multipliers = append(multipliers, multiplier)
+			fieldStart = i + 1
+		case '}':
+			multipliers = multipliers[:len(multipliers)-1]
+			fieldStart = i + 1
+		}
+	}
+	return total
+}
+
+// TestDiscussionQueries_NodeBudget 测试 Discussion 相关查询的最大节点数不超过 GitHub 的限制
+func TestDiscussionQueries_NodeBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		want      int
+		overLimit bool
+	}{
+		// labels 100 + comments 100 + replies 100×50
+		{name: "discussion", query: discussionQuery, want: 5200},
+		// comments 100 + replies 100×50
+		{name: "comments", query: commentsQuery, want: 5100},
+		{name: "replies", query: repliesQuery, want: 100},
+		{
+			name:      "nested reaction connections",
+			query:     `{ comments(first: 100) { reactions(first: 100) { nodes { content } } replies(first: 100) { reactions(first: 100) { nodes { content } } } } }`,
+			want:      100 + 100*100 + 100*100 + 100*100*100,
+			overLimit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := graphQLNodeBudget(tt.query)
+			if got != tt.want {
+				t.Errorf("node budget = %d, want %d", got, tt.want)
+			}
+			if (got > graphQLNodeLimit) != tt.overLimit {
+				t.Errorf("node budget %d vs GitHub limit %d: over limit = %v, want %v", got, graphQLNodeLimit, got > graphQLNodeLimit, tt.overLimit)
+			}
+		})
+	}
+}
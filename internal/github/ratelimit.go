@@ -0,0 +1,117 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRateLimitRetries 触发限流后自动等待并重试的最大次数
+const maxRateLimitRetries = 3
+
+// defaultSecondaryRateLimitWait 次级限流未给出 Retry-After 时的等待时间（GitHub 建议至少等待一分钟）
+const defaultSecondaryRateLimitWait = time.Minute
+
+// RateLimitError GitHub API 限流错误，可通过 errors.Is(err, ErrAPIRateLimit) 判断
+type RateLimitError struct {
+	StatusCode int           // HTTP 状态码（403 或 429；GraphQL 限流为 200）
+	Secondary  bool          // 是否为次级限流（滥用检测）
+	Reset      time.Time     // 主限流的限额重置时间
+	RetryAfter time.Duration // 次级限流要求的等待时间
+}
+
+// Error 实现 error 接口
+func (e *RateLimitError) Error() string {
+	if e.Secondary {
+		return fmt.Sprintf("%v (secondary, retry after %s)", ErrAPIRateLimit, e.RetryAfter)
+	}
+	return fmt.Sprintf("%v (resets at %s)", ErrAPIRateLimit, e.Reset.Format(time.RFC3339))
+}
+
+// Unwrap 返回 ErrAPIRateLimit
+func (e *RateLimitError) Unwrap() error {
+	return ErrAPIRateLimit
+}
+
+// Wait 返回从 now 起需要等待多久才能重试
+func (e *RateLimitError) Wait(now time.Time) time.Duration {
+	if e.Secondary {
+		return e.RetryAfter
+	}
+	if wait := e.Reset.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// parseRateLimit 根据状态码和限流响应头识别限流，不是限流响应时返回 nil
+//   - Retry-After：次级限流，等待指定秒数
+//   - X-RateLimit-Remaining 为 0：主限流，等待到 X-RateLimit-Reset
+//   - 没有上述响应头的 429：按次级限流处理
+func parseRateLimit(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		wait := defaultSecondaryRateLimitWait
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return &RateLimitError{StatusCode: resp.StatusCode, Secondary: true, RetryAfter: wait}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		rateLimitErr := &RateLimitError{StatusCode: resp.StatusCode}
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rateLimitErr.Reset = time.Unix(reset, 0)
+		}
+		return rateLimitErr
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{StatusCode: resp.StatusCode, Secondary: true, RetryAfter: defaultSecondaryRateLimitWait}
+	}
+
+	return nil
+}
+
+// parseGraphQLRateLimit 识别 GraphQL 限流：GitHub 返回 200，errors[].type 为 RATE_LIMITED，不是限流响应时返回 nil
+// 等待时间同样取自限流响应头：优先 Retry-After，其次 X-RateLimit-Reset，都没有时按次级限流处理
+func parseGraphQLRateLimit(resp *http.Response, body []byte) *RateLimitError {
+	var response struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+
+	limited := false
+	for _, gqlErr := range response.Errors {
+		if gqlErr.Type == GraphQLErrorRateLimited {
+			limited = true
+			break
+		}
+	}
+	if !limited {
+		return nil
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		wait := defaultSecondaryRateLimitWait
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return &RateLimitError{StatusCode: resp.StatusCode, Secondary: true, RetryAfter: wait}
+	}
+
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return &RateLimitError{StatusCode: resp.StatusCode, Reset: time.Unix(reset, 0)}
+	}
+
+	return &RateLimitError{StatusCode: resp.StatusCode, Secondary: true, RetryAfter: defaultSecondaryRateLimitWait}
+}
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestParseRateLimit 测试从响应识别主/次级限流
+func TestParseRateLimit(t *testing.T) {
+	reset := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    *RateLimitError
+	}{
+		{
+			name:    "primary rate limit",
+			status:  http.StatusForbidden,
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+			want:    &RateLimitError{StatusCode: http.StatusForbidden, Reset: reset},
+		},
+		{
+			name:    "secondary rate limit with retry-after",
+			status:  http.StatusForbidden,
+			headers: map[string]string{"Retry-After": "30"},
+			want:    &RateLimitError{StatusCode: http.StatusForbidden, Secondary: true, RetryAfter: 30 * time.Second},
+		},
+		{
+			name:   "429 without headers",
+			status: http.StatusTooManyRequests,
+			want:   &RateLimitError{StatusCode: http.StatusTooManyRequests, Secondary: true, RetryAfter: time.Minute},
+		},
+		{
+			name:    "plain forbidden",
+			status:  http.StatusForbidden,
+			headers: map[string]string{"X-RateLimit-Remaining": "42"},
+			want:    nil,
+		},
+		{
+			name:    "success with exhausted quota",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-RateLimit-Remaining": "0"},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got := parseRateLimit(resp)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("expected no rate limit, got %+v", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("parseRateLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseGraphQLRateLimit 测试从 GraphQL 响应识别限流
+func TestParseGraphQLRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+		want    *RateLimitError
+	}{
+		{
+			name:    "primary rate limit",
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+			body:    `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`,
+			want:    &RateLimitError{StatusCode: http.StatusOK, Reset: time.Unix(1700000000, 0)},
+		},
+		{
+			name:    "retry-after",
+			headers: map[string]string{"Retry-After": "30"},
+			body:    `{"errors":[{"type":"RATE_LIMITED","message":"slow down"}]}`,
+			want:    &RateLimitError{StatusCode: http.StatusOK, Secondary: true, RetryAfter: 30 * time.Second},
+		},
+		{
+			name: "without headers",
+			body: `{"errors":[{"type":"RATE_LIMITED","message":"slow down"}]}`,
+			want: &RateLimitError{StatusCode: http.StatusOK, Secondary: true, RetryAfter: time.Minute},
+		},
+		{
+			name:    "other errors",
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+			body:    `{"data":null,"errors":[{"type":"NOT_FOUND","message":"missing"}]}`,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got := parseGraphQLRateLimit(resp, []byte(tt.body))
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("expected no rate limit, got %+v", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("parseGraphQLRateLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSend_RateLimit 测试限流时返回带类型的错误，或在允许的等待时间内休眠后重试
+func TestSend_RateLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		rateLimitWait time.Duration
+		wantErr       bool
+		wantSleeps    []time.Duration
+	}{
+		{name: "fail fast by default", rateLimitWait: 0, wantErr: true},
+		{name: "reset too far away", rateLimitWait: time.Minute, wantErr: true},
+		{name: "wait until reset and retry", rateLimitWait: time.Hour, wantSleeps: []time.Duration{10 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", "1700000600")
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Write([]byte(`{"title":"ok"}`))
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("", WithBaseURL(mockServer.URL), WithRateLimitWait(tt.rateLimitWait))
+			client.now = func() time.Time { return now }
+			var sleeps []time.Duration
+			client.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			var v struct {
+				Title string `json:"title"`
+			}
+			err := client.get(context.Background(), mockServer.URL+"/x", &v)
+
+			if tt.wantErr {
+				var rateLimitErr *RateLimitError
+				if !errors.Is(err, ErrAPIRateLimit) || !errors.As(err, &rateLimitErr) {
+					t.Fatalf("expected RateLimitError, got %v", err)
+				}
+				if !rateLimitErr.Reset.Equal(now.Add(10 * time.Minute)) {
+					t.Errorf("expected reset time in error, got %v", rateLimitErr.Reset)
+				}
+				if len(sleeps) != 0 {
+					t.Errorf("expected no sleep, got %v", sleeps)
+				}
+				return
+			}
+
+			if err != nil || v.Title != "ok" {
+				t.Fatalf("expected retry to succeed, got %v (%+v)", err, v)
+			}
+			if len(sleeps) != len(tt.wantSleeps) || sleeps[0] != tt.wantSleeps[0] {
+				t.Errorf("expected sleeps %v, got %v", tt.wantSleeps, sleeps)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy 瞬时故障（网络错误、5xx）的重试策略，重试间隔按指数退避增长
+type RetryPolicy struct {
+	MaxAttempts int           // 最多尝试次数（含首次请求），小于等于 1 表示不重试
+	BaseDelay   time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxDelay    time.Duration // 单次等待时间上限，0 表示不限制
+	Jitter      float64       // 随机抖动比例（0-1），等待时间在 ±Jitter 范围内浮动
+	MaxElapsed  time.Duration // 从首次请求起允许重试的总时长，0 表示不限制
+}
+
+// DefaultRetryPolicy 返回默认的重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		MaxElapsed:  time.Minute,
+	}
+}
+
+// backoff 返回第 attempt 次请求失败后的等待时间
+// random 为 [0, 1) 的随机数，用于计算抖动
+func (p RetryPolicy) backoff(attempt int, random float64) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + p.Jitter*(2*random-1)))
+	}
+	return delay
+}
+
+// StatusError 非预期的 HTTP 状态码
+type StatusError struct {
+	StatusCode int
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// isRetryable 判断错误是否为可重试的瞬时故障：网络错误（连接重置、超时等）和 5xx 响应
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrNetwork) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy_Backoff 测试指数退避、上限和抖动
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		random  float64
+		want    time.Duration
+	}{
+		{name: "first retry", policy: policy, attempt: 1, random: 0.5, want: time.Second},
+		{name: "doubles", policy: policy, attempt: 2, random: 0.5, want: 2 * time.Second},
+		{name: "doubles again", policy: policy, attempt: 3, random: 0.5, want: 4 * time.Second},
+		{name: "capped", policy: policy, attempt: 10, random: 0.5, want: 5 * time.Second},
+		{name: "jitter low", policy: RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}, attempt: 1, random: 0, want: 500 * time.Millisecond},
+		{name: "jitter high", policy: RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}, attempt: 1, random: 1, want: 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.attempt, tt.random); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSend_Retry 测试 5xx 和网络错误的重试
+func TestSend_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // 依次返回的状态码
+		policy       RetryPolicy
+		wantErr      bool
+		wantRequests int
+		wantSleeps   []time.Duration
+	}{
+		{
+			name:         "recovers after 502 and 503",
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			policy:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second},
+			wantRequests: 3,
+			wantSleeps:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			policy:       RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second},
+			wantErr:      true,
+			wantRequests: 2,
+			wantSleeps:   []time.Duration{time.Second},
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusUnprocessableEntity, http.StatusOK},
+			policy:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "stops when max elapsed would be exceeded",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			policy:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxElapsed: time.Second},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[requests]
+				requests++
+				w.WriteHeader(status)
+				w.Write([]byte(`{}`))
+			}))
+			defer mockServer.Close()
+
+			client := NewClient("", WithBaseURL(mockServer.URL), WithRetryPolicy(tt.policy))
+			var sleeps []time.Duration
+			client.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			var v map[string]interface{}
+			err := client.get(context.Background(), mockServer.URL+"/x", &v)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, requests)
+			}
+			if len(sleeps) != len(tt.wantSleeps) {
+				t.Fatalf("expected sleeps %v, got %v", tt.wantSleeps, sleeps)
+			}
+			for i := range sleeps {
+				if sleeps[i] != tt.wantSleeps[i] {
+					t.Errorf("expected sleeps %v, got %v", tt.wantSleeps, sleeps)
+				}
+			}
+		})
+	}
+}
+
+// TestSend_RetryNetworkError 测试网络错误会重试，且重试过程输出到详细日志
+func TestSend_RetryNetworkError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := mockServer.URL + "/x"
+	mockServer.Close() // 关闭后连接会被拒绝
+
+	var logs bytes.Buffer
+	client := NewClient("",
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+		WithLogger(log.New(&logs, "", 0)),
+	)
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	var v map[string]interface{}
+	err := client.get(context.Background(), url, &v)
+	if !errors.Is(err, ErrNetwork) {
+		t.Fatalf("expected ErrNetwork, got %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "attempt 1/3 failed") || !strings.Contains(logs.String(), "attempt 2/3 failed") {
+		t.Errorf("expected retry attempts in verbose log, got:\n%s", logs.String())
+	}
+}
+
+// TestFetchIssueContext_Canceled 测试 ctx 取消会中止请求和重试等待
+func TestFetchIssueContext_Canceled(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	client := NewClient("", WithBaseURL(mockServer.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}))
+
+	t.Run("already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := client.FetchIssueContext(ctx, "owner", "repo", 1); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("canceled while waiting to retry", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.FetchIssueContext(ctx, "owner", "repo", 1)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected retry wait to be interrupted, took %v", elapsed)
+		}
+	})
+}
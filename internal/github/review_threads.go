@@ -0,0 +1,117 @@
+package github
+
+import "context"
+
+// reviewThreadState 代码会话的状态，以会话首条评论的 ID 关联 REST 数据
+type reviewThreadState struct {
+	Resolved   bool
+	Outdated   bool
+	ResolvedBy User
+}
+
+// reviewThreadsQuery 查询 PR 的一页代码会话状态
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+	repository(owner: $owner, name: $name) {
+		pullRequest(number: $number) {
+			reviewThreads(first: 100, after: $after) {
+				` + pageInfoFields + `
+				nodes {
+					isResolved
+					isOutdated
+					resolvedBy {
+						__typename
+						login
+						url
+					}
+					comments(first: 1) {
+						nodes {
+							databaseId
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+// fetchReviewThreadStates 通过 GraphQL 获取 PR 所有代码会话的解决/过时状态
+// 返回以会话首条评论 ID（databaseId）为键的状态表
+func (c *Client) fetchReviewThreadStates(ctx context.Context, owner, repo string, number int) (map[int64]reviewThreadState, error) {
+	states := make(map[int64]reviewThreadState)
+
+	after := ""
+	for {
+		variables := map[string]interface{}{
+			"owner":  owner,
+			"name":   repo,
+			"number": number,
+			"after":  graphQLCursor(after),
+		}
+
+		var data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						PageInfo graphQLPageInfo `json:"pageInfo"`
+						Nodes    []struct {
+							IsResolved bool          `json:"isResolved"`
+							IsOutdated bool          `json:"isOutdated"`
+							ResolvedBy *graphQLActor `json:"resolvedBy"`
+							Comments   struct {
+								Nodes []struct {
+									DatabaseID int64 `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+
+		if err := c.graphQL(ctx, reviewThreadsQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		pr := data.Repository.PullRequest
+		if pr == nil {
+			return nil, ErrResourceNotFound
+		}
+
+		for _, node := range pr.ReviewThreads.Nodes {
+			if len(node.Comments.Nodes) == 0 {
+				continue
+			}
+			state := reviewThreadState{
+				Resolved: node.IsResolved,
+				Outdated: node.IsOutdated,
+			}
+			if node.ResolvedBy != nil {
+				state.ResolvedBy = node.ResolvedBy.toUser()
+			}
+			states[node.Comments.Nodes[0].DatabaseID] = state
+		}
+
+		if !pr.ReviewThreads.PageInfo.HasNextPage {
+			break
+		}
+		after = pr.ReviewThreads.PageInfo.EndCursor
+	}
+
+	return states, nil
+}
+
+// applyReviewThreadStates 将会话状态写入时间线中对应的会话首条评论（包括嵌套在 Review 下的）
+func applyReviewThreadStates(comments []Comment, states map[int64]reviewThreadState) {
+	for i := range comments {
+		comment := &comments[i]
+		if comment.Kind == CommentKindReviewComment && comment.InReplyToID == 0 {
+			if state, ok := states[comment.ID]; ok {
+				comment.Resolved = state.Resolved
+				comment.Outdated = state.Outdated
+				comment.ResolvedBy = state.ResolvedBy
+			}
+			continue
+		}
+		applyReviewThreadStates(comment.Replies, states)
+	}
+}
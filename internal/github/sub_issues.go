@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// restIssueRef REST API 返回的关联 Issue（子 Issue、父 Issue）
+type restIssueRef struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	State       string `json:"state"`
+	StateReason string `json:"state_reason"`
+	HTMLURL     string `json:"html_url"`
+}
+
+// toIssueRef 转换为通用的 Issue 摘要
+func (r restIssueRef) toIssueRef() IssueRef {
+	return IssueRef{
+		Number:      r.Number,
+		Title:       r.Title,
+		State:       r.State,
+		StateReason: r.StateReason,
+		URL:         r.HTMLURL,
+	}
+}
+
+// restSubIssuesSummary Issue 接口返回的子 Issue 统计（旧版 GHES 没有该字段）
+type restSubIssuesSummary struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// fetchSubIssues 获取 Issue 的全部子 Issue（跟随分页）
+func (c *Client) fetchSubIssues(ctx context.Context, owner, repo string, number int) ([]IssueRef, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/sub_issues", c.baseURL, owner, repo, number)
+	refs, err := getAll[restIssueRef](ctx, c, url)
+	if err != nil {
+		return nil, err
+	}
+
+	subIssues := make([]IssueRef, len(refs))
+	for i, ref := range refs {
+		subIssues[i] = ref.toIssueRef()
+	}
+	return subIssues, nil
+}
+
+// fetchParentIssue 获取 Issue 的父 Issue，没有父 Issue 时返回 ErrResourceNotFound
+func (c *Client) fetchParentIssue(ctx context.Context, owner, repo string, number int) (*IssueRef, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/parent", c.baseURL, owner, repo, number)
+	var ref restIssueRef
+	if err := c.get(ctx, url, &ref); err != nil {
+		return nil, err
+	}
+	if ref.Number == 0 {
+		return nil, ErrResourceNotFound
+	}
+
+	parent := ref.toIssueRef()
+	return &parent, nil
+}
+
+// taskItemPattern 匹配任务列表项，如 "- [ ] 待办" 或 "* [x] 已完成"
+var taskItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.+?)\s*$`)
+
+// parseTasks 解析正文中的任务列表项（忽略代码块中的内容）
+func parseTasks(body string) []Task {
+	tasks := []Task{}
+	fence := ""
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// 跳过围栏代码块，闭合围栏须与开启围栏使用相同字符且不短于开启围栏
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			for len(fence) < len(trimmed) && trimmed[len(fence)] == fence[0] {
+				fence += fence[:1]
+			}
+			continue
+		}
+
+		if match := taskItemPattern.FindStringSubmatch(line); match != nil {
+			tasks = append(tasks, Task{Text: match[2], Done: match[1] != " "})
+		}
+	}
+	return tasks
+}
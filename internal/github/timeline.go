@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimelineEventType Issue 时间线事件类型
+type TimelineEventType string
+
+// 支持的时间线事件（其余事件类型会被忽略，评论另行获取）
+const (
+	EventLabeled         TimelineEventType = "labeled"
+	EventUnlabeled       TimelineEventType = "unlabeled"
+	EventAssigned        TimelineEventType = "assigned"
+	EventUnassigned      TimelineEventType = "unassigned"
+	EventMilestoned      TimelineEventType = "milestoned"
+	EventDemilestoned    TimelineEventType = "demilestoned"
+	EventRenamed         TimelineEventType = "renamed"
+	EventClosed          TimelineEventType = "closed"
+	EventReopened        TimelineEventType = "reopened"
+	EventReferenced      TimelineEventType = "referenced"
+	EventCrossReferenced TimelineEventType = "cross-referenced"
+)
+
+// supportedEvents 会被保留的时间线事件类型
+var supportedEvents = map[TimelineEventType]bool{
+	EventLabeled:         true,
+	EventUnlabeled:       true,
+	EventAssigned:        true,
+	EventUnassigned:      true,
+	EventMilestoned:      true,
+	EventDemilestoned:    true,
+	EventRenamed:         true,
+	EventClosed:          true,
+	EventReopened:        true,
+	EventReferenced:      true,
+	EventCrossReferenced: true,
+}
+
+// fetchTimelineEvents 获取 Issue 时间线中的事件（不含评论），按时间顺序返回
+func (c *Client) fetchTimelineEvents(ctx context.Context, owner, repo string, number int) ([]TimelineEvent, error) {
+	timelineURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/timeline", c.baseURL, owner, repo, number)
+	eventsData, err := getAll[restTimelineEvent](ctx, c, timelineURL)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []TimelineEvent{}
+	for _, eData := range eventsData {
+		if !supportedEvents[eData.Event] {
+			continue
+		}
+		events = append(events, eData.toEvent())
+	}
+	return events, nil
+}
+
+// restTimelineEvent REST API 返回的时间线事件
+type restTimelineEvent struct {
+	Event     TimelineEventType `json:"event"`
+	Actor     *restUser         `json:"actor"`
+	CreatedAt time.Time         `json:"created_at"`
+	Label     *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Assignee  *restUser `json:"assignee"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Rename *struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	CommitID    string `json:"commit_id"`
+	StateReason string `json:"state_reason"`
+	Source      *struct {
+		Issue *struct {
+			Title   string `json:"title"`
+			Number  int    `json:"number"`
+			HTMLURL string `json:"html_url"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+// toEvent 转换为通用时间线事件
+func (e restTimelineEvent) toEvent() TimelineEvent {
+	event := TimelineEvent{
+		Type:        e.Event,
+		CreatedAt:   e.CreatedAt,
+		CommitSHA:   e.CommitID,
+		StateReason: e.StateReason,
+	}
+	// 操作者账号已删除时 actor 为 null
+	event.Actor = ghostUser()
+	if e.Actor != nil {
+		event.Actor = e.Actor.toUser()
+	}
+	if e.Label != nil {
+		event.Label = e.Label.Name
+	}
+	if e.Assignee != nil {
+		event.Assignee = e.Assignee.toUser()
+	}
+	if e.Milestone != nil {
+		event.Milestone = e.Milestone.Title
+	}
+	if e.Rename != nil {
+		event.RenameFrom = e.Rename.From
+		event.RenameTo = e.Rename.To
+	}
+	if e.Source != nil && e.Source.Issue != nil {
+		event.SourceTitle = e.Source.Issue.Title
+		event.SourceNumber = e.Source.Issue.Number
+		event.SourceURL = e.Source.Issue.HTMLURL
+	}
+	return event
+}
@@ -6,6 +6,26 @@ import "time"
 type User struct {
 	Login   string
 	HTMLURL string
+	Kind    UserKind // 为空时视为普通用户
+}
+
+// UserKind 用户账号类型
+type UserKind string
+
+const (
+	UserKindUser      UserKind = "user"
+	UserKindBot       UserKind = "bot"       // GitHub App 等机器人账号，Login 不含 "[bot]" 后缀
+	UserKindGhost     UserKind = "ghost"     // 已删除的账号，统一显示为 ghost
+	UserKindMannequin UserKind = "mannequin" // 迁移导入时创建的占位账号
+)
+
+// ghostLogin GitHub 用于替代已删除账号的用户名
+const ghostLogin = "ghost"
+
+// ghostUser 返回表示已删除账号的用户
+// 不设置 HTMLURL：ghost 页面的地址取决于实例（GHES 不在 github.com），且没有实际的个人主页
+func ghostUser() User {
+	return User{Login: ghostLogin, Kind: UserKindGhost}
 }
 
 // Reaction 评论的reaction
@@ -14,45 +34,258 @@ type Reaction struct {
 	Count   int
 }
 
+// ReactionContents 全部 reaction 类型，按 GitHub 界面中的显示顺序排列
+var ReactionContents = []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"}
+
+// AuthorAssociation 作者与仓库的关系
+type AuthorAssociation string
+
+const (
+	AssociationOwner                AuthorAssociation = "OWNER"
+	AssociationMember               AuthorAssociation = "MEMBER"
+	AssociationCollaborator         AuthorAssociation = "COLLABORATOR"
+	AssociationContributor          AuthorAssociation = "CONTRIBUTOR"
+	AssociationFirstTimeContributor AuthorAssociation = "FIRST_TIME_CONTRIBUTOR"
+	AssociationFirstTimer           AuthorAssociation = "FIRST_TIMER"
+	AssociationMannequin            AuthorAssociation = "MANNEQUIN"
+	AssociationNone                 AuthorAssociation = "NONE"
+)
+
+// IsMaintainer 判断是否为仓库维护者（所有者、组织成员或协作者）
+func (a AuthorAssociation) IsMaintainer() bool {
+	return a == AssociationOwner || a == AssociationMember || a == AssociationCollaborator
+}
+
+// CommentKind 评论类型，用于区分 PR 时间线中不同来源的条目
+type CommentKind string
+
+const (
+	CommentKindIssue         CommentKind = "issue_comment"  // 主讨论区的对话评论
+	CommentKindReview        CommentKind = "review"         // Review 总结
+	CommentKindReviewComment CommentKind = "review_comment" // 代码行级 Review 评论
+)
+
+// ReviewState PR Review 的结论
+type ReviewState string
+
+const (
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewStateCommented        ReviewState = "COMMENTED"
+	ReviewStateDismissed        ReviewState = "DISMISSED"
+)
+
 // Comment 通用评论（适用于Issue、PR、Discussion）
 type Comment struct {
-	ID        int64
-	User      User
-	CreatedAt time.Time
-	Body      string
-	Reactions []Reaction
-	Deleted   bool // 标记是否已删除
+	ID                int64
+	NodeID            string      // GraphQL 全局节点 ID，用于批量查询编辑信息
+	Kind              CommentKind // Discussion 评论为空
+	User              User
+	AuthorAssociation AuthorAssociation
+
+	CreatedAt   time.Time
+	EditHistory // 最后编辑时间、编辑者和历史版本
+	Body        string
+	Reactions   []Reaction
+	Deleted     bool        // 标记是否已删除（已删除但仍有回复的 Discussion 评论）
+	ReviewState ReviewState // 仅 Kind 为 review 时有效
+	Replies     []Comment   // 嵌套的子评论（如 Review 下的行级评论），按时间排序
+
+	// 以下字段来自 GraphQL（需要 Token），Review 总结不支持隐藏
+	Minimized       bool   // 被维护者隐藏（折叠）
+	MinimizedReason string // 隐藏原因：spam、off-topic、outdated、resolved、duplicate、abuse
+
+	// 以下字段仅 Kind 为 review_comment 时有效
+	Path        string // 评论所在文件
+	Line        int    // 评论所在行（0 表示文件级评论）
+	DiffHunk    string // 评论所在位置的 diff 片段
+	InReplyToID int64  // 回复的会话首条评论 ID，首条评论为 0
+
+	// 以下字段仅代码会话首条评论有效（来自 GraphQL reviewThreads）
+	Resolved   bool // 会话已解决
+	Outdated   bool // 所在代码已变更
+	ResolvedBy User // 解决会话的用户
+
+	// 以下字段仅 Discussion 评论有效
+	IsAnswer    bool // 被采纳为答案（Q&A 分类）
+	UpvoteCount int
 }
 
 // Issue GitHub Issue
 type Issue struct {
-	Title     string
-	URL       string
-	User      User
-	CreatedAt time.Time
-	State     string // "open", "closed"
-	Body      string
-	Comments  []Comment
+	Title             string
+	URL               string
+	User              User
+	AuthorAssociation AuthorAssociation
+	CreatedAt         time.Time
+	EditHistory              // 正文的最后编辑时间、编辑者和历史版本
+	State             string // "open", "closed"
+	Metadata                 // 标签、指派、里程碑、关闭信息等
+	Body              string
+	Reactions         []Reaction // 正文的 reactions
+	Comments          []Comment
+	Events            []TimelineEvent // 时间线事件（标签、指派、关闭等），按时间排序
+
+	// 任务跟踪
+	SubIssues []IssueRef // 子 Issue，按 GitHub 中的顺序排列
+	Parent    *IssueRef  // 父 Issue，没有时为 nil
+	Tasks     []Task     // 正文中的任务列表项
+}
+
+// IssueRef 关联 Issue 的摘要（子 Issue、父 Issue）
+type IssueRef struct {
+	Number      int
+	Title       string
+	State       string // "open", "closed"
+	StateReason string // completed / not_planned / duplicate
+	URL         string
+}
+
+// Task 正文中的任务列表项（"- [ ] 待办" / "- [x] 已完成"）
+type Task struct {
+	Text string
+	Done bool
+}
+
+// Progress 返回子 Issue 和任务列表的完成数与总数（已关闭的子 Issue 视为完成）
+func (i *Issue) Progress() (completed, total int) {
+	for _, subIssue := range i.SubIssues {
+		if subIssue.State == "closed" {
+			completed++
+		}
+	}
+	for _, task := range i.Tasks {
+		if task.Done {
+			completed++
+		}
+	}
+	return completed, len(i.SubIssues) + len(i.Tasks)
+}
+
+// Metadata Issue/PR/Discussion 共有的分类与状态信息（不适用的字段为零值）
+type Metadata struct {
+	Labels       []string
+	Assignees    []User
+	Milestone    string
+	ClosedAt     *time.Time
+	ClosedBy     User
+	StateReason  string // Issue：completed / not_planned / reopened；Discussion：resolved / outdated / duplicate
+	Locked       bool
+	CommentCount int // API 统计的评论数
+}
+
+// EditHistory 正文或评论的编辑信息
+type EditHistory struct {
+	EditedAt  *time.Time // 最后编辑时间，未编辑时为 nil
+	Editor    User       // 最后编辑者，未知时为空
+	Revisions []Revision // 历史版本按时间升序，第一个为原始内容（仅启用编辑历史时获取）
+}
+
+// Revision 正文或评论的一个历史版本
+type Revision struct {
+	EditedAt time.Time
+	Editor   User
+	Body     string // 该版本的完整内容
+}
+
+// TimelineEvent Issue 时间线中的事件，按类型填写对应字段
+type TimelineEvent struct {
+	Type         TimelineEventType
+	Actor        User
+	CreatedAt    time.Time
+	Label        string // labeled / unlabeled
+	Assignee     User   // assigned / unassigned
+	Milestone    string // milestoned / demilestoned
+	RenameFrom   string // renamed
+	RenameTo     string // renamed
+	StateReason  string // closed：completed / not_planned
+	CommitSHA    string // referenced / closed（由提交关闭时）
+	SourceTitle  string // cross-referenced：引用方 Issue/PR 的标题
+	SourceNumber int    // cross-referenced：引用方编号
+	SourceURL    string // cross-referenced：引用方链接
+}
+
+// ChangedFile PR 中变更的文件
+type ChangedFile struct {
+	Filename  string
+	Status    string // "added", "removed", "modified", "renamed", "copied", "changed", "unchanged"
+	Additions int
+	Deletions int
+	Patch     string // 统一 diff 格式的补丁，二进制文件或补丁过大时为空
+}
+
+// Commit PR 中的提交
+type Commit struct {
+	SHA        string
+	URL        string
+	Message    string
+	AuthorName string    // git 提交中的作者名
+	Author     User      // 关联的 GitHub 用户，提交邮箱未关联账号时为空
+	Date       time.Time // 作者提交时间
+}
+
+// CheckKind CI 检查的来源
+type CheckKind string
+
+const (
+	CheckKindCheckRun CheckKind = "check_run" // GitHub Checks API（如 GitHub Actions）
+	CheckKindStatus   CheckKind = "status"    // Commit Status API（如外部 CI）
+)
+
+// Check PR 最新提交上的 CI 检查结果
+type Check struct {
+	Name        string
+	Kind        CheckKind
+	Status      string     // check run: "queued", "in_progress", "completed"；commit status 为空
+	Conclusion  string     // "success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required", "error", "pending"
+	StartedAt   *time.Time // 仅 check run
+	CompletedAt *time.Time // 仅 check run
+	DetailsURL  string
 }
 
 // PullRequest GitHub Pull Request
 type PullRequest struct {
-	Title     string
-	URL       string
-	User      User
-	CreatedAt time.Time
-	State     string // "open", "closed", "merged"
-	Body      string
-	Comments  []Comment // 仅Review评论
+	Title             string
+	URL               string
+	User              User
+	AuthorAssociation AuthorAssociation
+	CreatedAt         time.Time
+	EditHistory              // 正文的最后编辑时间、编辑者和历史版本
+	State             string // "open", "closed", "merged"
+	Metadata                 // 标签、指派、里程碑、关闭信息等
+	Body              string
+	Reactions         []Reaction // 正文的 reactions
+	Comments          []Comment  // 对话评论、Review 总结和 Review 评论，按时间排序
+	Files             []ChangedFile
+
+	// 分支与合并信息
+	Draft          bool
+	BaseRef        string
+	HeadRef        string
+	HeadSHA        string
+	MergeCommitSHA string
+	MergedBy       User
+	MergedAt       *time.Time
+	Commits        []Commit
+	Checks         []Check // HEAD 提交上的 CI 检查
 }
 
 // Discussion GitHub Discussion
 type Discussion struct {
-	Title     string
-	URL       string
-	User      User
-	CreatedAt time.Time
-	State     string // "open", "closed"
-	Body      string
-	Comments  []Comment // 包含主楼和所有回复，按时间排序
+	Title             string
+	URL               string
+	User              User
+	AuthorAssociation AuthorAssociation
+	CreatedAt         time.Time
+	EditHistory              // 正文的最后编辑时间、编辑者和历史版本
+	State             string // "open", "closed"
+	Metadata                 // 标签、关闭信息等（Discussion 没有指派和里程碑）
+	Body              string
+	Reactions         []Reaction // 正文的 reactions
+	Comments          []Comment  // 顶层评论按时间排序，回复嵌套在各自评论的 Replies 中
+
+	Category       string
+	UpvoteCount    int
+	AnswerChosenBy User       // 采纳答案的用户，未采纳时为空
+	AnswerChosenAt *time.Time // 采纳答案的时间，未采纳时为 nil
 }
@@ -14,9 +14,12 @@ var (
 	ErrUnsupportedResourceType = errors.New("unsupported resource type")
 )
 
+// DefaultHost 始终允许的 GitHub 主机
+const DefaultHost = "github.com"
+
 // parseAndValidateURL 解析URL并验证基础格式
 // 返回路径分段和解析后的URL对象
-func parseAndValidateURL(rawURL string) ([]string, *url.URL, error) {
+func parseAndValidateURL(rawURL string, hosts []string) ([]string, *url.URL, error) {
 	// 处理空字符串
 	if rawURL == "" {
 		return nil, nil, ErrInvalidURLFormat
@@ -28,9 +31,10 @@ func parseAndValidateURL(rawURL string) ([]string, *url.URL, error) {
 		return nil, nil, fmt.Errorf("parse URL %q failed: %w", rawURL, ErrInvalidURLFormat)
 	}
 
-	// 验证host必须是github.com
-	if parsed.Host != "github.com" {
-		return nil, nil, fmt.Errorf("host must be github.com, got %q: %w", parsed.Host, ErrInvalidURLFormat)
+	// 验证host必须是github.com或允许的GitHub Enterprise Server主机
+	if !allowedHost(parsed.Host, hosts) {
+		return nil, nil, fmt.Errorf("host must be %s, got %q: %w",
+			strings.Join(append([]string{DefaultHost}, hosts...), " or "), parsed.Host, ErrInvalidURLFormat)
 	}
 
 	// 分割路径，去掉开头的空字符串
@@ -75,6 +79,16 @@ func parseAndValidateURL(rawURL string) ([]string, *url.URL, error) {
 	return parts, parsed, nil
 }
 
+// allowedHost 判断主机是否为 github.com 或 hosts 之一（不区分大小写）
+func allowedHost(host string, hosts []string) bool {
+	for _, allowed := range append([]string{DefaultHost}, hosts...) {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseURL 解析GitHub URL并返回Resource
 //
 // 支持的URL格式：
@@ -82,11 +96,13 @@ func parseAndValidateURL(rawURL string) ([]string, *url.URL, error) {
 //   - PR:         https://github.com/{owner}/{repo}/pull/{number}
 //   - Discussion: https://github.com/{owner}/{repo}/discussions/{number}
 //
+// hosts 为额外允许的 GitHub Enterprise Server 主机名，github.com 始终允许。
+//
 // 返回错误：
 //   - ErrInvalidURLFormat: URL格式无效
 //   - ErrUnsupportedResourceType: 不支持的资源类型
-func ParseURL(rawURL string) (*Resource, error) {
-	parts, parsed, err := parseAndValidateURL(rawURL)
+func ParseURL(rawURL string, hosts ...string) (*Resource, error) {
+	parts, parsed, err := parseAndValidateURL(rawURL, hosts)
 	if err != nil {
 		return nil, err
 	}
@@ -122,10 +138,12 @@ func ParseURL(rawURL string) (*Resource, error) {
 	}
 
 	// 构建干净的URL（去掉query和fragment，保留原始大小写）
+	host := strings.ToLower(parsed.Host)
 	cleanURL := fmt.Sprintf("https://%s/%s/%s/%s/%d",
-		parsed.Host, owner, repo, parts[2], number)
+		host, owner, repo, parts[2], number)
 
 	return &Resource{
+		Host:        host,
 		Type:        resType,
 		Owner:       owner,
 		Repo:        repo,
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -296,3 +297,63 @@ func TestParseURL(t *testing.T) {
 		})
 	}
 }
+
+// TestParseURL_EnterpriseHosts 测试允许额外的 GitHub Enterprise Server 主机
+func TestParseURL_EnterpriseHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		hosts    []string
+		wantHost string
+		wantErr  error
+	}{
+		{
+			name:     "github.com always allowed",
+			url:      "https://github.com/owner/repo/issues/1",
+			hosts:    []string{"ghe.example.com"},
+			wantHost: "github.com",
+		},
+		{
+			name:     "enterprise host allowed",
+			url:      "https://ghe.example.com/team/project/pull/7",
+			hosts:    []string{"ghe.example.com"},
+			wantHost: "ghe.example.com",
+		},
+		{
+			name:     "host match is case-insensitive",
+			url:      "https://GHE.Example.com/team/project/discussions/3",
+			hosts:    []string{"ghe.example.com"},
+			wantHost: "ghe.example.com",
+		},
+		{
+			name:    "enterprise host rejected when not configured",
+			url:     "https://ghe.example.com/team/project/issues/1",
+			wantErr: ErrInvalidURLFormat,
+		},
+		{
+			name:    "other hosts still rejected",
+			url:     "https://gitlab.com/owner/repo/issues/1",
+			hosts:   []string{"ghe.example.com"},
+			wantErr: ErrInvalidURLFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseURL(tt.url, tt.hosts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Host != tt.wantHost {
+				t.Errorf("ParseURL() Host = %v, want %v", got.Host, tt.wantHost)
+			}
+			if !strings.HasPrefix(got.OriginalURL, "https://"+tt.wantHost+"/") {
+				t.Errorf("ParseURL() OriginalURL = %v, want host %v", got.OriginalURL, tt.wantHost)
+			}
+		})
+	}
+}
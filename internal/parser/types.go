@@ -26,6 +26,7 @@ func (rt ResourceType) String() string {
 
 // Resource 解析后的GitHub资源
 type Resource struct {
+	Host        string // 主机名（github.com 或 GitHub Enterprise Server 的主机名）
 	Type        ResourceType
 	Owner       string
 	Repo        string